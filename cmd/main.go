@@ -12,7 +12,9 @@ import (
 	"SIE-SRC/config"
 	"SIE-SRC/domain"
 	"SIE-SRC/services/delivery"
+	"SIE-SRC/services/notification"
 	"SIE-SRC/services/repository"
+	"SIE-SRC/services/storage"
 	"SIE-SRC/services/usecase"
 
 	"github.com/gofiber/fiber/v2"
@@ -47,6 +49,9 @@ func main() {
 func startHTTPServer() {
 	app := fiber.New(config.GetFiberConfig())
 
+	// Webhook Slack untuk notifikasi (stok rendah, dll)
+	notification.InitSlack()
+
 	// MongoDB connection
 	mongoURI := config.GetMongoConnString()
 	clientOptions := options.Client().ApplyURI(mongoURI)
@@ -65,7 +70,8 @@ func startHTTPServer() {
 	app.Use(cors.New(cors.Config{
 		AllowOrigins:     os.Getenv("CORS_ALLOW_ORIGINS"),
 		AllowMethods:     "GET,POST,PUT,DELETE",
-		AllowHeaders:     "Content-Type, Authorization",
+		AllowHeaders:     "Content-Type, Authorization, If-Match",
+		ExposeHeaders:    "ETag",
 		AllowCredentials: false,
 	}))
 	app.Use(limiter.New(limiter.Config{
@@ -95,17 +101,87 @@ func startHTTPServer() {
 	produkUseCase := usecase.NewUseCaseProduk(produkRepo, algoritmaRepo, 10*time.Second)
 	delivery.NewHttpDeliveryProduk(app, produkUseCase)
 
+	// Gambar Produk: disimpan di folder lokal dan disajikan sebagai file statis
+	fileStorage := storage.NewLocalStorage(config.GetStorageDir(), config.GetStorageBaseURL())
+	app.Static(config.GetStorageBaseURL(), config.GetStorageDir(), fiber.Static{
+		MaxAge: 86400,
+	})
+	gambarUseCase := usecase.NewUseCaseProdukGambar(produkRepo, fileStorage, int64(config.GetImageMaxSize()), config.GetImageMaxPixels(), config.GetThumbnailSize(), 30*time.Second)
+	delivery.NewHttpDeliveryProdukGambar(app, gambarUseCase)
+
+	// Barcode dan Label Produk route
+	barcodeUseCase := usecase.NewUseCaseBarcode(produkRepo, 30*time.Second)
+	delivery.NewHttpDeliveryBarcode(app, barcodeUseCase)
+
+	// Kategori Repository dan Use Case route
+	kategoriRepo := repository.NewMongoRepoKategori(db)
+	kategoriUseCase := usecase.NewUseCaseKategori(kategoriRepo, 30*time.Second)
+	delivery.NewHttpDeliveryKategori(app, kategoriUseCase)
+
+	// Produk Induk (varian) Repository dan Use Case route
+	indukRepo := repository.NewMongoRepoProdukInduk(db)
+	indukUseCase := usecase.NewUseCaseProdukInduk(indukRepo, produkRepo, 10*time.Second)
+	delivery.NewHttpDeliveryProdukInduk(app, indukUseCase)
+
+	// Promo Repository dan Use Case route
+	promoRepo := repository.NewMongoRepoPromo(db)
+	promoUseCase := usecase.NewUseCasePromo(promoRepo, 10*time.Second)
+	delivery.NewHttpDeliveryPromo(app, promoUseCase)
+
 	// Penjualan Repository dan Use Case route
 	penjualanRepo := repository.NewMongoRepoPenjualan(db, produkRepo)
 	penjualanUseCase := usecase.NewUseCasePenjualan(penjualanRepo, 10*time.Second)
 	delivery.NewHttpDeliveryPenjualan(app, penjualanUseCase)
 
+	// QRIS dinamis per penjualan
+	qrisUseCase := usecase.NewUseCaseQRIS(penjualanRepo, config.GetQRISMerchant(), 10*time.Second)
+	delivery.NewHttpDeliveryQRIS(app, qrisUseCase)
+
+	// Struk penjualan (pdf, ESC/POS, text)
+	receiptUseCase := usecase.NewUseCaseReceipt(penjualanRepo, config.GetStoreInfo(), config.GetReceiptWidth(), 10*time.Second)
+	delivery.NewHttpDeliveryReceipt(app, receiptUseCase)
+
+	// Batch dan Kadaluarsa Repository dan Use Case route
+	batchRepo := repository.NewMongoRepoStockBatch(db)
+	batchUseCase := usecase.NewUseCaseStockBatch(batchRepo, 10*time.Second)
+	delivery.NewHttpDeliveryStockBatch(app, batchUseCase)
+
+	// Stock Opname Repository dan Use Case route
+	opnameRepo := repository.NewMongoRepoOpname(db, produkRepo)
+	opnameUseCase := usecase.NewUseCaseOpname(opnameRepo, 10*time.Second)
+	delivery.NewHttpDeliveryOpname(app, opnameUseCase)
+
+	// Supplier dan Purchasing Repository dan Use Case route
+	supplierRepo := repository.NewMongoRepoSupplier(db)
+	supplierUseCase := usecase.NewUseCaseSupplier(supplierRepo, 10*time.Second)
+	delivery.NewHttpDeliverySupplier(app, supplierUseCase)
+
+	purchaseRepo := repository.NewMongoRepoPurchase(db, produkRepo, supplierRepo)
+	purchaseUseCase := usecase.NewUseCasePurchase(purchaseRepo, 10*time.Second)
+	delivery.NewHttpDeliveryPurchase(app, purchaseUseCase)
+
+	// Laporan Repository dan Use Case route
+	reportRepo := repository.NewMongoRepoReport(db)
+	reportUseCase := usecase.NewUseCaseReport(reportRepo, 30*time.Second)
+	delivery.NewHttpDeliveryReport(app, reportUseCase)
+
+	// Riwayat dan Jadwal Harga Repository dan Use Case route
+	priceHistoryRepo := repository.NewMongoRepoPriceHistory(db)
+	priceScheduleRepo := repository.NewMongoRepoPriceSchedule(db, produkRepo)
+	priceUseCase := usecase.NewUseCasePrice(produkRepo, priceHistoryRepo, priceScheduleRepo, 30*time.Second)
+	delivery.NewHttpDeliveryPrice(app, priceUseCase)
+
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	defer stopScheduler()
+	go runPriceScheduler(schedulerCtx, priceUseCase, config.GetPriceSchedulerInterval())
+
 	// Signal handling for graceful shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	go func() {
 		<-quit
 		log.Println("Shutting down server...")
+		stopScheduler()
 		if err := app.Shutdown(); err != nil {
 			log.Fatalf("Server shutdown error: %v", err)
 		}
@@ -118,3 +194,24 @@ func startHTTPServer() {
 		log.Fatal("Server startup error:", err)
 	}
 }
+
+// runPriceScheduler menerapkan jadwal perubahan harga yang sudah jatuh tempo secara berkala
+func runPriceScheduler(ctx context.Context, uc domain.PriceUseCase, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		applied, err := uc.ApplyDueSchedules(ctx)
+		if err != nil {
+			log.Printf("Error applying price schedules: %v", err)
+		} else if applied > 0 {
+			log.Printf("Applied %d scheduled price changes", applied)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
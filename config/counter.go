@@ -0,0 +1,48 @@
+package config
+
+import (
+	"os"
+	"strconv"
+)
+
+func GetProdukIDPrefix() string {
+	return os.Getenv("PRODUK_ID_PREFIX")
+}
+
+func GetProdukIDWidth() int {
+	return getIDWidth("PRODUK_ID_WIDTH")
+}
+
+func GetPenjualanIDPrefix() string {
+	env, ok := os.LookupEnv("PENJUALAN_ID_PREFIX")
+	if ok {
+		return env
+	}
+	return "PJ"
+}
+
+func GetPenjualanIDWidth() int {
+	return getIDWidth("PENJUALAN_ID_WIDTH")
+}
+
+func getIDWidth(key string) int {
+	env := os.Getenv(key)
+	if env != "" {
+		width, err := strconv.Atoi(env)
+		if err == nil && width > 0 {
+			return width
+		}
+	}
+	return 3
+}
+
+// GetBarcodePrefix adalah awalan EAN-13 internal; 20-29 dicadangkan GS1 untuk penggunaan di dalam toko
+func GetBarcodePrefix() string {
+	env := os.Getenv("BARCODE_PREFIX")
+	if env != "" {
+		if _, err := strconv.Atoi(env); err == nil && len(env) < 12 {
+			return env
+		}
+	}
+	return "20"
+}
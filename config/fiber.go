@@ -16,6 +16,7 @@ func GetFiberConfig() fiber.Config {
 	return fiber.Config{
 		JSONEncoder: json.Marshal,
 		JSONDecoder: json.Unmarshal,
+		BodyLimit:   getBodyLimit(),
 	}
 }
 
@@ -0,0 +1,22 @@
+package config
+
+import "os"
+
+func GetSlackWebhookURL() string {
+	return os.Getenv("SLACK_WEBHOOK_URL")
+}
+
+func GetSlackChannel() string {
+	return os.Getenv("SLACK_CHANNEL")
+}
+
+func GetSlackUsername() string {
+	env := os.Getenv("SLACK_USERNAME")
+	if env != "" {
+		return env
+	}
+	if name := GetAppName(); name != "" {
+		return name
+	}
+	return "SIE-SRC"
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"SIE-SRC/domain"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// GetPajakSetting membaca pengaturan PPN toko:
+// PPN_MODE (inklusif/eksklusif, default inklusif), PPN_TARIF (persen, default 11),
+// PPN_PEMBULATAN (normal/bawah/atas, default normal)
+func GetPajakSetting() domain.PajakSetting {
+	setting := domain.PajakSetting{
+		Mode:         domain.PajakModeInklusif,
+		TarifDefault: 11,
+		Pembulatan:   domain.PembulatanNormal,
+	}
+
+	switch mode := strings.ToLower(os.Getenv("PPN_MODE")); mode {
+	case domain.PajakModeInklusif, domain.PajakModeEksklusif:
+		setting.Mode = mode
+	}
+
+	if env := os.Getenv("PPN_TARIF"); env != "" {
+		tarif, err := strconv.ParseFloat(env, 64)
+		if err == nil && tarif >= 0 && tarif <= 100 {
+			setting.TarifDefault = tarif
+		}
+	}
+
+	switch pembulatan := strings.ToLower(os.Getenv("PPN_PEMBULATAN")); pembulatan {
+	case domain.PembulatanNormal, domain.PembulatanBawah, domain.PembulatanAtas:
+		setting.Pembulatan = pembulatan
+	}
+
+	return setting
+}
@@ -0,0 +1,34 @@
+package config
+
+import (
+	"SIE-SRC/domain"
+	"os"
+	"strings"
+)
+
+// GetQRISMerchant membaca data merchant QRIS dari environment
+func GetQRISMerchant() domain.QRISMerchant {
+	merchant := domain.QRISMerchant{
+		Nama:       os.Getenv("QRIS_MERCHANT_NAME"),
+		Kota:       os.Getenv("QRIS_MERCHANT_CITY"),
+		KodePos:    os.Getenv("QRIS_POSTAL_CODE"),
+		MCC:        os.Getenv("QRIS_MCC"),
+		NMID:       os.Getenv("QRIS_NMID"),
+		Kriteria:   strings.ToUpper(os.Getenv("QRIS_CRITERIA")),
+		GUI:        os.Getenv("QRIS_ACQUIRER_GUI"),
+		MerchantID: os.Getenv("QRIS_MERCHANT_ID"),
+		PAN:        os.Getenv("QRIS_MERCHANT_PAN"),
+	}
+
+	if merchant.Nama == "" {
+		merchant.Nama = GetAppName()
+	}
+	if merchant.MCC == "" {
+		merchant.MCC = "5411" // toko kelontong / supermarket
+	}
+	if merchant.Kriteria == "" {
+		merchant.Kriteria = domain.QRISKriteriaMikro
+	}
+
+	return merchant
+}
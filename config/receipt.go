@@ -0,0 +1,39 @@
+package config
+
+import (
+	"SIE-SRC/domain"
+	"os"
+	"strconv"
+)
+
+// GetStoreInfo membaca identitas toko untuk struk penjualan
+func GetStoreInfo() domain.StoreInfo {
+	store := domain.StoreInfo{
+		Nama:    os.Getenv("STORE_NAME"),
+		Alamat:  os.Getenv("STORE_ADDRESS"),
+		Telepon: os.Getenv("STORE_PHONE"),
+		NPWP:    os.Getenv("STORE_NPWP"),
+		Footer:  os.Getenv("RECEIPT_FOOTER"),
+	}
+
+	if store.Nama == "" {
+		store.Nama = GetAppName()
+	}
+	if store.Footer == "" {
+		store.Footer = "Terima kasih atas kunjungan Anda"
+	}
+
+	return store
+}
+
+// GetReceiptWidth adalah lebar kertas struk default dalam mm (58 atau 80)
+func GetReceiptWidth() int {
+	env := os.Getenv("RECEIPT_WIDTH")
+	if env != "" {
+		width, err := strconv.Atoi(env)
+		if err == nil && (width == domain.ReceiptWidth58 || width == domain.ReceiptWidth80) {
+			return width
+		}
+	}
+	return domain.ReceiptWidth58
+}
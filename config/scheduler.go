@@ -0,0 +1,18 @@
+package config
+
+import (
+	"os"
+	"time"
+)
+
+// GetPriceSchedulerInterval mengatur seberapa sering jadwal harga diperiksa (contoh: "30s", "5m")
+func GetPriceSchedulerInterval() time.Duration {
+	env := os.Getenv("PRICE_SCHEDULER_INTERVAL")
+	if env != "" {
+		interval, err := time.ParseDuration(env)
+		if err == nil && interval > 0 {
+			return interval
+		}
+	}
+	return time.Minute
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"os"
+	"strconv"
+)
+
+const defaultBodyLimit = 4 * 1024 * 1024
+
+// GetStorageDir adalah folder penyimpanan lokal untuk file unggahan
+func GetStorageDir() string {
+	env := os.Getenv("STORAGE_DIR")
+	if env != "" {
+		return env
+	}
+	return "./uploads"
+}
+
+// GetStorageBaseURL adalah prefix URL tempat file unggahan disajikan
+func GetStorageBaseURL() string {
+	env := os.Getenv("STORAGE_BASE_URL")
+	if env != "" {
+		return env
+	}
+	return "/media"
+}
+
+// GetImageMaxSize adalah ukuran maksimum gambar produk dalam byte
+func GetImageMaxSize() int {
+	env := os.Getenv("IMAGE_MAX_SIZE")
+	if env != "" {
+		size, err := strconv.Atoi(env)
+		if err == nil && size > 0 {
+			return size
+		}
+	}
+	return 2 * 1024 * 1024
+}
+
+// GetThumbnailSize adalah sisi terpanjang thumbnail gambar produk dalam pixel
+func GetThumbnailSize() int {
+	env := os.Getenv("THUMBNAIL_SIZE")
+	if env != "" {
+		size, err := strconv.Atoi(env)
+		if err == nil && size > 0 {
+			return size
+		}
+	}
+	return 256
+}
+
+// GetImageMaxPixels adalah jumlah pixel (lebar x tinggi) maksimum gambar produk.
+// Dibatasi karena gambar didekode penuh ke memori untuk membuat thumbnail.
+func GetImageMaxPixels() int {
+	env := os.Getenv("IMAGE_MAX_PIXELS")
+	if env != "" {
+		pixels, err := strconv.Atoi(env)
+		if err == nil && pixels > 0 {
+			return pixels
+		}
+	}
+	return 25 * 1000 * 1000
+}
+
+// getBodyLimit memastikan batas body request Fiber cukup untuk unggahan gambar
+func getBodyLimit() int {
+	limit := GetImageMaxSize() + 512*1024
+	if limit < defaultBodyLimit {
+		return defaultBodyLimit
+	}
+	return limit
+}
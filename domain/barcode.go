@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	BarcodeFormatCode128 = "code128"
+	BarcodeFormatEAN13   = "ean13"
+)
+
+// EAN13CheckDigit menghitung digit pemeriksa dari 12 digit pertama EAN-13
+func EAN13CheckDigit(digits string) int {
+	sum := 0
+	for i, r := range digits {
+		d := int(r - '0')
+		if i%2 == 1 {
+			d *= 3
+		}
+		sum += d
+	}
+	return (10 - sum%10) % 10
+}
+
+// IsEAN13 memeriksa apakah kode terdiri dari 13 digit dengan digit pemeriksa yang benar
+func IsEAN13(code string) bool {
+	if len(code) != 13 {
+		return false
+	}
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return EAN13CheckDigit(code[:12]) == int(code[12]-'0')
+}
+
+// InternalEAN13 menyusun EAN-13 internal dari awalan dan nomor urut counter.
+// Error dikembalikan jika awalan bukan angka atau nomor urut sudah tidak muat di 12 digit.
+func InternalEAN13(prefix string, seq int64) (string, error) {
+	if prefix == "" || len(prefix) > 11 {
+		return "", fmt.Errorf("awalan barcode internal harus 1 sampai 11 digit")
+	}
+	for _, r := range prefix {
+		if r < '0' || r > '9' {
+			return "", fmt.Errorf("awalan barcode internal %s harus berupa angka", prefix)
+		}
+	}
+	if seq <= 0 {
+		return "", fmt.Errorf("nomor urut barcode harus lebih dari 0")
+	}
+
+	body := fmt.Sprintf("%s%0*d", prefix, 12-len(prefix), seq)
+	if len(body) > 12 {
+		return "", fmt.Errorf("nomor barcode internal dengan awalan %s sudah habis", prefix)
+	}
+	return fmt.Sprintf("%s%d", body, EAN13CheckDigit(body)), nil
+}
+
+// ProdukBarcode adalah barcode internal yang diberikan ke produk
+type ProdukBarcode struct {
+	IDProduk   string `json:"id_produk"`
+	NamaProduk string `json:"nama_produk"`
+	KodeProduk string `json:"barcode_produk"`
+}
+
+// LabelItem adalah produk dan jumlah label yang dicetak
+type LabelItem struct {
+	IDProduk string `json:"id_produk"`
+	Jumlah   int    `json:"jumlah"`
+}
+
+// BarcodeImage adalah opsi gambar barcode; format kosong berarti dipilih otomatis
+type BarcodeImage struct {
+	Format string
+	Width  int
+	Height int
+}
+
+type BarcodeUseCase interface {
+	GetImage(ctx context.Context, idProduk string, opts BarcodeImage) ([]byte, error)
+	GenerateLabels(ctx context.Context, items []LabelItem) ([]byte, error)
+	GenerateMissing(ctx context.Context) ([]ProdukBarcode, error)
+}
@@ -0,0 +1,80 @@
+package domain_test
+
+import (
+	"SIE-SRC/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEAN13CheckDigit(t *testing.T) {
+	tests := []struct {
+		code string
+		want int
+	}{
+		{code: "4006381333931", want: 1},
+		{code: "5901234123457", want: 7},
+		{code: "9780306406157", want: 7},
+		{code: "0012345678905", want: 5},
+		{code: "2000000000008", want: 8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			assert.Equal(t, tt.want, domain.EAN13CheckDigit(tt.code[:12]))
+		})
+	}
+}
+
+func TestIsEAN13(t *testing.T) {
+	tests := []struct {
+		code string
+		want bool
+	}{
+		{code: "4006381333931", want: true},
+		{code: "5901234123457", want: true},
+		{code: "4006381333932", want: false},
+		{code: "400638133393", want: false},
+		{code: "40063813339311", want: false},
+		{code: "40063813339A1", want: false},
+		{code: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			assert.Equal(t, tt.want, domain.IsEAN13(tt.code))
+		})
+	}
+}
+
+func TestInternalEAN13(t *testing.T) {
+	tests := []struct {
+		name    string
+		prefix  string
+		seq     int64
+		want    string
+		wantErr bool
+	}{
+		{name: "nomor pertama", prefix: "20", seq: 1, want: "2000000000015"},
+		{name: "nomor terakhir awalan 2 digit", prefix: "20", seq: 9999999999, want: "2099999999998"},
+		{name: "awalan 2 digit habis", prefix: "20", seq: 10000000000, wantErr: true},
+		{name: "awalan panjang", prefix: "29912345", seq: 42, want: "2991234500429"},
+		{name: "awalan panjang habis", prefix: "29912345", seq: 10000, wantErr: true},
+		{name: "awalan bukan angka", prefix: "2A", seq: 1, wantErr: true},
+		{name: "awalan kosong", prefix: "", seq: 1, wantErr: true},
+		{name: "awalan terlalu panjang", prefix: "200000000000", seq: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := domain.InternalEAN13(tt.prefix, tt.seq)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.True(t, domain.IsEAN13(got))
+		})
+	}
+}
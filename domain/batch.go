@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// StockBatch adalah sebagian stok produk dengan tanggal kadaluarsa yang sama
+type StockBatch struct {
+	ID                primitive.ObjectID `json:"id_batch" bson:"_id,omitempty"`
+	IDProduk          string             `json:"id_produk" bson:"id_produk"`
+	NamaProduk        string             `json:"nama_produk" bson:"nama_produk"`
+	NoBatch           string             `json:"no_batch" bson:"no_batch"`
+	TanggalKadaluarsa time.Time          `json:"tanggal_kadaluarsa" bson:"tanggal_kadaluarsa"`
+	JumlahAwal        int                `json:"jumlah_awal" bson:"jumlah_awal"`
+	Jumlah            int                `json:"jumlah" bson:"jumlah"`
+	RefID             string             `json:"ref_id" bson:"ref_id"`
+	TanggalMasuk      time.Time          `json:"tanggal_masuk" bson:"tanggal_masuk"`
+	UpdatedAt         time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+// BatchConsumption mencatat jumlah yang diambil dari sebuah batch
+type BatchConsumption struct {
+	IDBatch           primitive.ObjectID `json:"id_batch" bson:"id_batch"`
+	NoBatch           string             `json:"no_batch" bson:"no_batch"`
+	TanggalKadaluarsa time.Time          `json:"tanggal_kadaluarsa" bson:"tanggal_kadaluarsa"`
+	Jumlah            int                `json:"jumlah" bson:"jumlah"`
+}
+
+type StockBatchRepository interface {
+	Create(ctx context.Context, bd *StockBatch) (StockBatch, error)
+	GetByProduk(ctx context.Context, idProduk string) ([]StockBatch, error)
+	GetExpiring(ctx context.Context, within time.Duration) ([]StockBatch, error)
+	// ConsumeFEFO mengambil stok dari batch dengan kadaluarsa paling awal lebih dulu.
+	// Sisa yang tidak tercakup batch dianggap stok tanpa batch.
+	ConsumeFEFO(ctx context.Context, idProduk string, kuantitas int) ([]BatchConsumption, error)
+	Restore(ctx context.Context, consumptions []BatchConsumption) error
+}
+
+type StockBatchUseCase interface {
+	Create(ctx context.Context, bd *StockBatch) (StockBatch, error)
+	GetByProduk(ctx context.Context, idProduk string) ([]StockBatch, error)
+	GetExpiring(ctx context.Context, days int) ([]StockBatch, error)
+}
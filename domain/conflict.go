@@ -0,0 +1,13 @@
+package domain
+
+import "fmt"
+
+// VersionConflictError dikembalikan saat update memakai versi dokumen yang sudah usang
+type VersionConflictError struct {
+	Expected int64       `json:"expected_version"`
+	Current  interface{} `json:"current"`
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("data sudah diubah oleh pengguna lain (versi yang dikirim: %d)", e.Expected)
+}
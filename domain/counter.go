@@ -0,0 +1,22 @@
+package domain
+
+import "context"
+
+// Counter menyimpan nomor urut terakhir untuk sebuah sequence ID
+type Counter struct {
+	Name string `json:"name" bson:"_id"`
+	Seq  int64  `json:"seq" bson:"seq"`
+}
+
+// CounterFormat mengatur prefix dan lebar zero-padding ID yang dihasilkan
+type CounterFormat struct {
+	Prefix string
+	Width  int
+}
+
+type CounterRepository interface {
+	// NextSequence menaikkan counter secara atomik sebanyak n dan mengembalikan nilai terakhir
+	NextSequence(ctx context.Context, name string, n int64) (int64, error)
+	// SeedSequence memastikan counter minimal bernilai seq (untuk data lama)
+	SeedSequence(ctx context.Context, name string, seq int64) error
+}
@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"context"
+	"io"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ProdukGambar adalah satu gambar produk; urutan di Produk.Gambar menentukan urutan tampil
+type ProdukGambar struct {
+	ID           primitive.ObjectID `json:"id_gambar" bson:"id_gambar"`
+	URL          string             `json:"url" bson:"url"`
+	ThumbnailURL string             `json:"thumbnail_url" bson:"thumbnail_url"`
+	ContentType  string             `json:"content_type" bson:"content_type"`
+	Key          string             `json:"-" bson:"key"`
+	ThumbnailKey string             `json:"-" bson:"thumbnail_key"`
+}
+
+// ProdukGambarUpload adalah file gambar yang diunggah untuk sebuah produk
+type ProdukGambarUpload struct {
+	IDProduk string
+	Size     int64
+	File     io.Reader
+}
+
+type ProdukGambarUseCase interface {
+	Upload(ctx context.Context, upload ProdukGambarUpload) (ProdukGambar, error)
+	Delete(ctx context.Context, idProduk, idGambar string) error
+	Reorder(ctx context.Context, idProduk string, ids []string) ([]ProdukGambar, error)
+}
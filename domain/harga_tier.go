@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// HargaTier adalah harga khusus per satuan dasar yang berlaku mulai MinJumlah (dalam satuan dasar).
+// Grup kosong berlaku untuk semua pelanggan; tier dengan Grup hanya berlaku untuk grup pelanggan tersebut,
+// sehingga harga grup pelanggan cukup ditulis sebagai tier dengan MinJumlah 1.
+type HargaTier struct {
+	Grup      string `json:"grup,omitempty" bson:"grup,omitempty"`
+	MinJumlah int    `json:"min_jumlah" bson:"min_jumlah"`
+	Harga     int    `json:"harga" bson:"harga"`
+}
+
+// NormalizeGrupPelanggan menyamakan penulisan nama grup pelanggan
+func NormalizeGrupPelanggan(grup string) string {
+	return strings.ToLower(strings.TrimSpace(grup))
+}
+
+// ValidateHargaTier memastikan tier harga produk konsisten
+func (p *Produk) ValidateHargaTier() error {
+	seen := make(map[string]bool, len(p.HargaTier))
+	for i, tier := range p.HargaTier {
+		if tier.MinJumlah < 1 {
+			return fmt.Errorf("minimal jumlah tier harga ke-%d harus lebih dari 0", i+1)
+		}
+		if tier.Harga <= 0 {
+			return fmt.Errorf("harga tier ke-%d harus lebih dari 0", i+1)
+		}
+
+		key := fmt.Sprintf("%s|%d", tier.Grup, tier.MinJumlah)
+		if seen[key] {
+			return fmt.Errorf("tier harga untuk grup %q mulai %d %s didefinisikan lebih dari sekali",
+				tier.Grup, tier.MinJumlah, p.NamaSatuanDasar())
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// ResolveHargaTier memilih tier termurah yang berlaku untuk grup pelanggan dan jumlah
+// (dalam satuan dasar). Nil jika tidak ada tier yang berlaku.
+func (p *Produk) ResolveHargaTier(grup string, jumlahDasar int) *HargaTier {
+	grup = NormalizeGrupPelanggan(grup)
+
+	var best *HargaTier
+	for i := range p.HargaTier {
+		tier := p.HargaTier[i]
+		if tier.Grup != "" && tier.Grup != grup {
+			continue
+		}
+		if jumlahDasar < tier.MinJumlah {
+			continue
+		}
+		if best == nil || tier.Harga < best.Harga {
+			best = &tier
+		}
+	}
+	return best
+}
@@ -0,0 +1,66 @@
+package domain_test
+
+import (
+	"SIE-SRC/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveHargaTier(t *testing.T) {
+	produk := domain.Produk{
+		Harga: 5000,
+		HargaTier: []domain.HargaTier{
+			{MinJumlah: 12, Harga: 4500},
+			{MinJumlah: 48, Harga: 4200},
+			{Grup: "member", MinJumlah: 1, Harga: 4800},
+			{Grup: "grosir", MinJumlah: 24, Harga: 4000},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		grup   string
+		jumlah int
+		want   *domain.HargaTier
+	}{
+		{name: "di bawah semua tier", grup: "", jumlah: 11, want: nil},
+		{name: "tepat di batas tier", grup: "", jumlah: 12, want: &domain.HargaTier{MinJumlah: 12, Harga: 4500}},
+		{name: "tier jumlah tertinggi", grup: "", jumlah: 100, want: &domain.HargaTier{MinJumlah: 48, Harga: 4200}},
+		{name: "harga grup untuk jumlah kecil", grup: "member", jumlah: 1, want: &domain.HargaTier{Grup: "member", MinJumlah: 1, Harga: 4800}},
+		{name: "tier umum lebih murah dari harga grup", grup: "member", jumlah: 12, want: &domain.HargaTier{MinJumlah: 12, Harga: 4500}},
+		{name: "nama grup dinormalisasi", grup: " GROSIR ", jumlah: 24, want: &domain.HargaTier{Grup: "grosir", MinJumlah: 24, Harga: 4000}},
+		{name: "tier grup lain tidak berlaku", grup: "member", jumlah: 24, want: &domain.HargaTier{MinJumlah: 12, Harga: 4500}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, produk.ResolveHargaTier(tt.grup, tt.jumlah))
+		})
+	}
+}
+
+func TestValidateHargaTier(t *testing.T) {
+	tests := []struct {
+		name    string
+		tiers   []domain.HargaTier
+		wantErr bool
+	}{
+		{name: "valid", tiers: []domain.HargaTier{{MinJumlah: 12, Harga: 4500}, {Grup: "member", MinJumlah: 12, Harga: 4400}}},
+		{name: "minimal jumlah nol", tiers: []domain.HargaTier{{MinJumlah: 0, Harga: 4500}}, wantErr: true},
+		{name: "harga nol", tiers: []domain.HargaTier{{MinJumlah: 12, Harga: 0}}, wantErr: true},
+		{name: "duplikat grup dan jumlah", tiers: []domain.HargaTier{{MinJumlah: 12, Harga: 4500}, {MinJumlah: 12, Harga: 4400}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			produk := domain.Produk{HargaTier: tt.tiers}
+			err := produk.ValidateHargaTier()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
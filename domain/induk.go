@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// ProdukInduk mengelompokkan beberapa varian produk (ukuran, warna, rasa, ...).
+// Setiap varian tetap berupa Produk dengan barcode, harga, dan stok sendiri
+// yang menunjuk ke induknya lewat IDInduk.
+type ProdukInduk struct {
+	IDInduk     string `json:"id_induk" bson:"_id"`
+	NamaProduk  string `json:"nama_produk" bson:"nama_produk"`
+	Kategori    string `json:"kategori" bson:"kategori"`
+	SubKategori string `json:"sub_kategori" bson:"sub_kategori"`
+	// Atribut adalah dimensi varian, misalnya ["ukuran", "warna"]
+	Atribut   []string  `json:"atribut" bson:"atribut"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// ProdukGroup adalah induk beserta variannya. Produk tanpa induk tampil sebagai grup sendiri
+// dengan Induk kosong.
+type ProdukGroup struct {
+	Induk     *ProdukInduk `json:"induk,omitempty"`
+	Varian    []Produk     `json:"varian"`
+	TotalStok int          `json:"total_stok"`
+	HargaMin  int          `json:"harga_min"`
+	HargaMax  int          `json:"harga_max"`
+}
+
+type ProdukIndukRepository interface {
+	Create(ctx context.Context, bd *ProdukInduk) (ProdukInduk, error)
+	GetAll(ctx context.Context) ([]ProdukInduk, error)
+	GetByID(ctx context.Context, id string) (*ProdukInduk, error)
+	Update(ctx context.Context, bd *ProdukInduk) error
+	Delete(ctx context.Context, id string) error
+}
+
+type ProdukIndukUseCase interface {
+	Create(ctx context.Context, bd *ProdukInduk) (ProdukInduk, error)
+	GetAll(ctx context.Context) ([]ProdukInduk, error)
+	GetByID(ctx context.Context, id string) (*ProdukGroup, error)
+	Update(ctx context.Context, bd *ProdukInduk) error
+	Delete(ctx context.Context, id string) error
+	GetGrouped(ctx context.Context, filter ProdukFilter) ([]ProdukGroup, error)
+}
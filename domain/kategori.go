@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Kategori adalah simpul pohon kategori produk.
+// Kategori utama tidak memiliki IDParent, sub kategori menunjuk ke kategori utamanya.
+// Produk tetap menyimpan nama kategori dan sub kategori agar data lama tetap terbaca.
+type Kategori struct {
+	IDKategori string    `json:"id_kategori" bson:"_id"`
+	Nama       string    `json:"nama" bson:"nama"`
+	IDParent   string    `json:"id_parent,omitempty" bson:"id_parent,omitempty"`
+	UpdatedAt  time.Time `json:"updated_at" bson:"updated_at"`
+	// TarifPajak adalah tarif PPN (persen) untuk produk di kategori ini; kosong = ikut induk/default
+	TarifPajak *float64 `json:"tarif_pajak,omitempty" bson:"tarif_pajak,omitempty"`
+}
+
+// KategoriNode adalah kategori beserta jumlah produk dan sub kategorinya
+type KategoriNode struct {
+	Kategori
+	JumlahProduk int            `json:"jumlah_produk"`
+	SubKategori  []KategoriNode `json:"sub_kategori,omitempty"`
+}
+
+// KategoriMergeResult merangkum hasil penggabungan kategori
+type KategoriMergeResult struct {
+	Target       Kategori `json:"target"`
+	ProdukDiubah int64    `json:"produk_diubah"`
+	SubDipindah  int      `json:"sub_dipindah"`
+	SubDigabung  int      `json:"sub_digabung"`
+}
+
+// KategoriCount adalah jumlah produk aktif untuk satu pasangan kategori dan sub kategori.
+// Nama dikembalikan dalam huruf kecil agar penulisan yang berbeda tetap terhitung sama.
+type KategoriCount struct {
+	Kategori    string `bson:"kategori"`
+	SubKategori string `bson:"sub_kategori"`
+	Jumlah      int    `bson:"jumlah"`
+}
+
+type KategoriRepository interface {
+	Create(ctx context.Context, bd *Kategori) (Kategori, error)
+	GetAll(ctx context.Context) ([]Kategori, error)
+	GetByID(ctx context.Context, id string) (*Kategori, error)
+	Rename(ctx context.Context, id string, nama string) (int64, error)
+	SetTarifPajak(ctx context.Context, id string, tarif *float64) error
+	Delete(ctx context.Context, id string) error
+	Merge(ctx context.Context, sourceID, targetID string) (KategoriMergeResult, error)
+	CountProduk(ctx context.Context) ([]KategoriCount, error)
+}
+
+type KategoriUseCase interface {
+	Create(ctx context.Context, bd *Kategori) (Kategori, error)
+	GetTree(ctx context.Context) ([]KategoriNode, error)
+	GetByID(ctx context.Context, id string) (*Kategori, error)
+	Rename(ctx context.Context, id string, nama string) (int64, error)
+	SetTarifPajak(ctx context.Context, id string, tarif *float64) error
+	Delete(ctx context.Context, id string) error
+	Merge(ctx context.Context, sourceID, targetID string) (KategoriMergeResult, error)
+}
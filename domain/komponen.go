@@ -0,0 +1,31 @@
+package domain
+
+// ProdukKomponen adalah satu komponen produk paket (kit/bundle).
+// Jumlah dalam satuan dasar komponen untuk setiap satu satuan dasar paket.
+type ProdukKomponen struct {
+	IDProduk   string `json:"id_produk" bson:"id_produk"`
+	NamaProduk string `json:"nama_produk,omitempty" bson:"nama_produk,omitempty"`
+	Jumlah     int    `json:"jumlah" bson:"jumlah"`
+}
+
+// IsKit menandakan produk paket yang stoknya dihitung dari komponennya
+func (p *Produk) IsKit() bool {
+	return len(p.Komponen) > 0
+}
+
+// KitStock menghitung jumlah paket utuh yang masih bisa dibentuk dari stok komponen
+// (dalam satuan dasar), yaitu dibatasi komponen yang paling sedikit. Komponen yang
+// tidak ada di stok atau stoknya negatif dianggap kosong.
+func (p *Produk) KitStock(stok map[string]int) int {
+	available := -1
+	for _, komponen := range p.Komponen {
+		count := 0
+		if stok[komponen.IDProduk] > 0 && komponen.Jumlah > 0 {
+			count = stok[komponen.IDProduk] / komponen.Jumlah
+		}
+		if available < 0 || count < available {
+			available = count
+		}
+	}
+	return max(available, 0)
+}
@@ -0,0 +1,43 @@
+package domain_test
+
+import (
+	"SIE-SRC/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKitStock(t *testing.T) {
+	paket := domain.Produk{
+		Komponen: []domain.ProdukKomponen{
+			{IDProduk: "P001", Jumlah: 2},
+			{IDProduk: "P002", Jumlah: 1},
+			{IDProduk: "P003", Jumlah: 3},
+		},
+	}
+
+	tests := []struct {
+		name string
+		stok map[string]int
+		want int
+	}{
+		{name: "dibatasi komponen paling sedikit", stok: map[string]int{"P001": 10, "P002": 7, "P003": 12}, want: 4},
+		{name: "sisa komponen dibulatkan ke bawah", stok: map[string]int{"P001": 9, "P002": 100, "P003": 100}, want: 4},
+		{name: "komponen habis", stok: map[string]int{"P001": 10, "P002": 0, "P003": 12}, want: 0},
+		{name: "stok komponen negatif", stok: map[string]int{"P001": 10, "P002": -3, "P003": 12}, want: 0},
+		{name: "komponen tidak ditemukan", stok: map[string]int{"P001": 10, "P002": 7}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, paket.KitStock(tt.stok))
+		})
+	}
+}
+
+func TestKitStockBukanPaket(t *testing.T) {
+	produk := domain.Produk{Stok: 10}
+
+	assert.False(t, produk.IsKit())
+	assert.Equal(t, 0, produk.KitStock(map[string]int{}))
+}
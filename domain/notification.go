@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// LowStockEvent dikirim saat stok produk turun melewati reorder point
+type LowStockEvent struct {
+	IDProduk     string    `json:"id_produk"`
+	NamaProduk   string    `json:"nama_produk"`
+	Stok         int       `json:"stok_barang"`
+	ReorderPoint int       `json:"reorder_point"`
+	ReorderQty   int       `json:"reorder_qty"`
+	Reason       string    `json:"reason"`
+	RefID        string    `json:"ref_id"`
+	Timestamp    time.Time `json:"timestamp"`
+}
+
+type Notifier interface {
+	NotifyLowStock(ctx context.Context, event LowStockEvent) error
+}
@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Status sesi stock opname
+const (
+	OpnameStatusOpen     = "open"
+	OpnameStatusApproved = "approved"
+)
+
+// OpnameItem adalah hasil hitung fisik satu produk dalam sesi opname
+type OpnameItem struct {
+	IDProduk     string `json:"id_produk" bson:"id_produk"`
+	KodeProduk   string `json:"barcode_produk" bson:"barcode_produk"`
+	NamaProduk   string `json:"nama_produk" bson:"nama_produk"`
+	StokFisik    int    `json:"stok_fisik" bson:"stok_fisik"`
+	StokSistem   int    `json:"stok_sistem" bson:"stok_sistem"`
+	Selisih      int    `json:"selisih" bson:"selisih"`
+	Harga        int    `json:"harga" bson:"harga"`
+	NilaiSelisih int    `json:"nilai_selisih" bson:"nilai_selisih"`
+	// DihitungPada adalah waktu hitung fisik; StokSistem dan Selisih dibekukan pada saat itu
+	DihitungPada *time.Time `json:"dihitung_pada,omitempty" bson:"dihitung_pada,omitempty"`
+	// StokBerubah menandai stok sistem yang berubah (penjualan/penerimaan) setelah item dihitung.
+	// Selisih tetap diposting sebagai delta sehingga transaksi tersebut tidak hilang.
+	StokBerubah bool `json:"stok_berubah,omitempty" bson:"stok_berubah,omitempty"`
+}
+
+type Opname struct {
+	IDOpname      string       `json:"id_opname" bson:"_id"`
+	Keterangan    string       `json:"keterangan" bson:"keterangan"`
+	Status        string       `json:"status" bson:"status"`
+	Items         []OpnameItem `json:"items" bson:"items"`
+	DibukaOleh    string       `json:"dibuka_oleh" bson:"dibuka_oleh"`
+	TanggalBuka   time.Time    `json:"tanggal_buka" bson:"tanggal_buka"`
+	DisetujuiOleh string       `json:"disetujui_oleh" bson:"disetujui_oleh"`
+	TanggalSetuju *time.Time   `json:"tanggal_setuju" bson:"tanggal_setuju"`
+	UpdatedAt     time.Time    `json:"updated_at" bson:"updated_at"`
+}
+
+// OpnameVariance adalah laporan selisih stok fisik terhadap stok sistem
+type OpnameVariance struct {
+	IDOpname          string       `json:"id_opname"`
+	Status            string       `json:"status"`
+	Items             []OpnameItem `json:"items"`
+	JumlahItem        int          `json:"jumlah_item"`
+	ItemSelisih       int          `json:"item_selisih"`
+	TotalSelisih      int          `json:"total_selisih"`
+	TotalNilaiSelisih int          `json:"total_nilai_selisih"`
+}
+
+type OpnameRepository interface {
+	Create(ctx context.Context, bd *Opname) (Opname, error)
+	GetAll(ctx context.Context) ([]Opname, error)
+	GetByID(ctx context.Context, id string) (*Opname, error)
+	SubmitCounts(ctx context.Context, id string, items []OpnameItem) (*Opname, error)
+	GetVariance(ctx context.Context, id string) (*OpnameVariance, error)
+	Approve(ctx context.Context, id string) (*OpnameVariance, error)
+}
+
+type OpnameUseCase interface {
+	Create(ctx context.Context, bd *Opname) (Opname, error)
+	GetAll(ctx context.Context) ([]Opname, error)
+	GetByID(ctx context.Context, id string) (*Opname, error)
+	SubmitCounts(ctx context.Context, id string, items []OpnameItem) (*Opname, error)
+	GetVariance(ctx context.Context, id string) (*OpnameVariance, error)
+	Approve(ctx context.Context, id string) (*OpnameVariance, error)
+}
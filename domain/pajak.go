@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Mode harga terhadap PPN
+const (
+	PajakModeInklusif  = "inklusif"  // harga jual sudah termasuk PPN
+	PajakModeEksklusif = "eksklusif" // PPN ditambahkan di atas harga jual
+)
+
+// Aturan pembulatan PPN ke rupiah penuh
+const (
+	PembulatanNormal = "normal" // setengah ke atas
+	PembulatanBawah  = "bawah"
+	PembulatanAtas   = "atas"
+)
+
+// PajakSetting adalah pengaturan PPN toko
+type PajakSetting struct {
+	Mode         string
+	TarifDefault float64 // persen, dipakai jika produk dan kategorinya tidak menentukan tarif
+	Pembulatan   string
+}
+
+// PajakRincian adalah DPP dan PPN per tarif dalam satu penjualan
+type PajakRincian struct {
+	Tarif float64 `json:"tarif" bson:"tarif"`
+	DPP   int     `json:"dpp" bson:"dpp"`
+	PPN   int     `json:"ppn" bson:"ppn"`
+}
+
+// ValidateTarifPajak memastikan tarif pajak (persen) berada di antara 0 dan 100
+func ValidateTarifPajak(tarif *float64) error {
+	if tarif != nil && (*tarif < 0 || *tarif > 100) {
+		return fmt.Errorf("tarif pajak harus antara 0 dan 100 persen")
+	}
+	return nil
+}
+
+// bulatkan membulatkan nilai pajak sesuai aturan. Toleransi kecil mencegah hasil
+// perhitungan float seperti 11.000000000000002 ikut dibulatkan ke atas.
+func (s PajakSetting) bulatkan(value float64) int {
+	const epsilon = 1e-9
+	switch s.Pembulatan {
+	case PembulatanBawah:
+		return int(math.Floor(value + epsilon))
+	case PembulatanAtas:
+		return int(math.Ceil(value - epsilon))
+	}
+	return int(math.Floor(value + 0.5 + epsilon))
+}
+
+// HitungPajak menghitung DPP dan PPN dari nilai bersih per tarif (setelah diskon).
+// Diskon transaksi dibagi proporsional ke setiap tarif; sisa pembagian masuk ke tarif terakhir.
+// PPN dihitung per tarif di tingkat transaksi (bukan per baris) lalu dibulatkan.
+// Mode inklusif: PPN = nilai x tarif / (100 + tarif), DPP = nilai - PPN.
+// Mode eksklusif: DPP = nilai, PPN = nilai x tarif / 100.
+func (s PajakSetting) HitungPajak(nilaiPerTarif map[float64]int, diskon int) []PajakRincian {
+	tarifs := make([]float64, 0, len(nilaiPerTarif))
+	for tarif := range nilaiPerTarif {
+		tarifs = append(tarifs, tarif)
+	}
+	sort.Float64s(tarifs)
+
+	bobot := make([]int, len(tarifs))
+	for i, tarif := range tarifs {
+		bobot[i] = nilaiPerTarif[tarif]
+	}
+	potongan := BagiProporsional(diskon, bobot)
+
+	rincian := make([]PajakRincian, 0, len(tarifs))
+	for i, tarif := range tarifs {
+		nilai := nilaiPerTarif[tarif] - potongan[i]
+
+		var dpp, ppn int
+		if s.Mode == PajakModeEksklusif {
+			dpp = nilai
+			ppn = s.bulatkan(float64(nilai) * tarif / 100)
+		} else {
+			ppn = s.bulatkan(float64(nilai) * tarif / (100 + tarif))
+			dpp = nilai - ppn
+		}
+
+		rincian = append(rincian, PajakRincian{Tarif: tarif, DPP: dpp, PPN: ppn})
+	}
+	return rincian
+}
+
+// BagiProporsional membagi jumlah ke setiap bobot secara proporsional (dibulatkan ke bawah).
+// Sisa pembagian masuk ke elemen terakhir sehingga jumlah hasil selalu sama dengan jumlah.
+func BagiProporsional(jumlah int, bobot []int) []int {
+	hasil := make([]int, len(bobot))
+	if len(bobot) == 0 {
+		return hasil
+	}
+
+	total := 0
+	for _, b := range bobot {
+		total += b
+	}
+
+	sisa := jumlah
+	for i, b := range bobot[:len(bobot)-1] {
+		if total > 0 {
+			hasil[i] = jumlah * b / total
+		}
+		sisa -= hasil[i]
+	}
+	hasil[len(bobot)-1] = sisa
+	return hasil
+}
@@ -0,0 +1,109 @@
+package domain_test
+
+import (
+	"SIE-SRC/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHitungPajakPembulatan(t *testing.T) {
+	tests := []struct {
+		name       string
+		mode       string
+		pembulatan string
+		nilai      int
+		want       domain.PajakRincian
+	}{
+		// 111000 x 11 / 111 = 11000 tepat; toleransi mencegah pembulatan atas menjadi 11001
+		{name: "inklusif tepat normal", mode: domain.PajakModeInklusif, pembulatan: domain.PembulatanNormal, nilai: 111000, want: domain.PajakRincian{Tarif: 11, DPP: 100000, PPN: 11000}},
+		{name: "inklusif tepat bawah", mode: domain.PajakModeInklusif, pembulatan: domain.PembulatanBawah, nilai: 111000, want: domain.PajakRincian{Tarif: 11, DPP: 100000, PPN: 11000}},
+		{name: "inklusif tepat atas", mode: domain.PajakModeInklusif, pembulatan: domain.PembulatanAtas, nilai: 111000, want: domain.PajakRincian{Tarif: 11, DPP: 100000, PPN: 11000}},
+		// 1000 x 11 / 111 = 99,099
+		{name: "inklusif pecahan kecil normal", mode: domain.PajakModeInklusif, pembulatan: domain.PembulatanNormal, nilai: 1000, want: domain.PajakRincian{Tarif: 11, DPP: 901, PPN: 99}},
+		{name: "inklusif pecahan kecil bawah", mode: domain.PajakModeInklusif, pembulatan: domain.PembulatanBawah, nilai: 1000, want: domain.PajakRincian{Tarif: 11, DPP: 901, PPN: 99}},
+		{name: "inklusif pecahan kecil atas", mode: domain.PajakModeInklusif, pembulatan: domain.PembulatanAtas, nilai: 1000, want: domain.PajakRincian{Tarif: 11, DPP: 900, PPN: 100}},
+		// 10000 x 11 / 111 = 990,99
+		{name: "inklusif pecahan besar bawah", mode: domain.PajakModeInklusif, pembulatan: domain.PembulatanBawah, nilai: 10000, want: domain.PajakRincian{Tarif: 11, DPP: 9010, PPN: 990}},
+		{name: "inklusif pecahan besar normal", mode: domain.PajakModeInklusif, pembulatan: domain.PembulatanNormal, nilai: 10000, want: domain.PajakRincian{Tarif: 11, DPP: 9009, PPN: 991}},
+		// 10050 x 11 / 100 = 1105,5
+		{name: "eksklusif setengah normal", mode: domain.PajakModeEksklusif, pembulatan: domain.PembulatanNormal, nilai: 10050, want: domain.PajakRincian{Tarif: 11, DPP: 10050, PPN: 1106}},
+		{name: "eksklusif setengah bawah", mode: domain.PajakModeEksklusif, pembulatan: domain.PembulatanBawah, nilai: 10050, want: domain.PajakRincian{Tarif: 11, DPP: 10050, PPN: 1105}},
+		{name: "eksklusif setengah atas", mode: domain.PajakModeEksklusif, pembulatan: domain.PembulatanAtas, nilai: 10050, want: domain.PajakRincian{Tarif: 11, DPP: 10050, PPN: 1106}},
+		// 10010 x 11 / 100 = 1101,1
+		{name: "eksklusif pecahan kecil normal", mode: domain.PajakModeEksklusif, pembulatan: domain.PembulatanNormal, nilai: 10010, want: domain.PajakRincian{Tarif: 11, DPP: 10010, PPN: 1101}},
+		{name: "eksklusif pecahan kecil atas", mode: domain.PajakModeEksklusif, pembulatan: domain.PembulatanAtas, nilai: 10010, want: domain.PajakRincian{Tarif: 11, DPP: 10010, PPN: 1102}},
+		{name: "pembulatan kosong dianggap normal", mode: domain.PajakModeEksklusif, pembulatan: "", nilai: 10050, want: domain.PajakRincian{Tarif: 11, DPP: 10050, PPN: 1106}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setting := domain.PajakSetting{Mode: tt.mode, Pembulatan: tt.pembulatan}
+			got := setting.HitungPajak(map[float64]int{tt.want.Tarif: tt.nilai}, 0)
+			assert.Equal(t, []domain.PajakRincian{tt.want}, got)
+		})
+	}
+}
+
+func TestHitungPajakDiskon(t *testing.T) {
+	tests := []struct {
+		name          string
+		mode          string
+		nilaiPerTarif map[float64]int
+		diskon        int
+		want          []domain.PajakRincian
+	}{
+		{
+			name:          "tanpa tarif",
+			mode:          domain.PajakModeInklusif,
+			nilaiPerTarif: map[float64]int{},
+			want:          []domain.PajakRincian{},
+		},
+		{
+			// 1000 x 30000 / 100001 = 299, sisa 701 masuk ke tarif terakhir
+			name:          "eksklusif dua tarif",
+			mode:          domain.PajakModeEksklusif,
+			nilaiPerTarif: map[float64]int{11: 70001, 0: 30000},
+			diskon:        1000,
+			want: []domain.PajakRincian{
+				{Tarif: 0, DPP: 29701, PPN: 0},
+				{Tarif: 11, DPP: 69300, PPN: 7623},
+			},
+		},
+		{
+			// Diskon 100 dibagi 33, 33, dan sisa 34 ke tarif terakhir
+			name:          "inklusif tiga tarif",
+			mode:          domain.PajakModeInklusif,
+			nilaiPerTarif: map[float64]int{11: 10000, 0: 10000, 5: 10000},
+			diskon:        100,
+			want: []domain.PajakRincian{
+				{Tarif: 0, DPP: 9967, PPN: 0},
+				{Tarif: 5, DPP: 9492, PPN: 475},
+				{Tarif: 11, DPP: 8978, PPN: 988},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setting := domain.PajakSetting{Mode: tt.mode, Pembulatan: domain.PembulatanNormal}
+			got := setting.HitungPajak(tt.nilaiPerTarif, tt.diskon)
+			assert.Equal(t, tt.want, got)
+
+			dpp, ppn := 0, 0
+			for _, rincian := range got {
+				dpp += rincian.DPP
+				ppn += rincian.PPN
+			}
+			nilai := 0
+			for _, v := range tt.nilaiPerTarif {
+				nilai += v
+			}
+			if tt.mode == domain.PajakModeInklusif {
+				assert.Equal(t, nilai-tt.diskon, dpp+ppn)
+			} else {
+				assert.Equal(t, nilai-tt.diskon, dpp)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Metode pembayaran penjualan
+const (
+	MetodeTunai    = "tunai"
+	MetodeKartu    = "kartu"
+	MetodeTransfer = "transfer"
+	MetodeEWallet  = "ewallet"
+	MetodeQRIS     = "qris"
+)
+
+// Pembayaran adalah satu pembayaran untuk penjualan; penjualan bisa dibayar dengan beberapa metode
+type Pembayaran struct {
+	Metode string `json:"metode" bson:"metode"`
+	// Jumlah adalah nominal yang dipakai untuk membayar transaksi
+	Jumlah int `json:"jumlah" bson:"jumlah"`
+	// Diterima dan Kembalian hanya untuk tunai; Diterima kosong berarti uang pas
+	Diterima  int    `json:"diterima,omitempty" bson:"diterima,omitempty"`
+	Kembalian int    `json:"kembalian,omitempty" bson:"kembalian,omitempty"`
+	Provider  string `json:"provider,omitempty" bson:"provider,omitempty"`   // bank, jaringan kartu, atau nama e-wallet
+	Referensi string `json:"referensi,omitempty" bson:"referensi,omitempty"` // kode approval / nomor referensi
+}
+
+// ApplyPembayaran memvalidasi pembayaran terhadap Total dan menghitung kembalian.
+// Pembayaran non-tunai tidak boleh melebihi sisa tagihan. Jumlah tunai yang kosong diisi
+// dengan sisa tagihan, sehingga kasir cukup mengisi uang yang diterima. Penjualan dengan
+// total 0 (misal seluruhnya tertutup promo) boleh disimpan tanpa pembayaran.
+func (bd *Penjualan) ApplyPembayaran() error {
+	if bd.Total == 0 && len(bd.Pembayaran) == 0 {
+		bd.Kembalian = 0
+		return nil
+	}
+	if len(bd.Pembayaran) == 0 {
+		return fmt.Errorf("pembayaran penjualan %s belum diisi", bd.IDPenjualan)
+	}
+
+	sisa := bd.Total
+	tunai := -1
+	for i := range bd.Pembayaran {
+		bayar := &bd.Pembayaran[i]
+		bayar.Metode = strings.ToLower(strings.TrimSpace(bayar.Metode))
+		bayar.Kembalian = 0
+
+		switch bayar.Metode {
+		case MetodeTunai:
+			if tunai >= 0 {
+				return fmt.Errorf("pembayaran tunai hanya boleh satu kali per transaksi")
+			}
+			tunai = i
+			continue
+		case MetodeKartu, MetodeTransfer, MetodeEWallet, MetodeQRIS:
+		default:
+			return fmt.Errorf("metode pembayaran %s tidak dikenal", bayar.Metode)
+		}
+
+		if bayar.Jumlah <= 0 {
+			return fmt.Errorf("jumlah pembayaran %s harus lebih dari 0", bayar.Metode)
+		}
+		if bayar.Jumlah > sisa {
+			return fmt.Errorf("pembayaran %s sebesar %d melebihi sisa tagihan %d", bayar.Metode, bayar.Jumlah, sisa)
+		}
+		bayar.Diterima = 0
+		sisa -= bayar.Jumlah
+	}
+
+	bd.Kembalian = 0
+	if tunai >= 0 {
+		bayar := &bd.Pembayaran[tunai]
+		if bayar.Jumlah < 0 || bayar.Diterima < 0 {
+			return fmt.Errorf("nominal pembayaran tunai tidak boleh negatif")
+		}
+		if bayar.Jumlah == 0 {
+			bayar.Jumlah = min(sisa, max(bayar.Diterima, 0))
+		}
+		if bayar.Jumlah == 0 {
+			return fmt.Errorf("jumlah pembayaran tunai harus lebih dari 0")
+		}
+		if bayar.Jumlah > sisa {
+			return fmt.Errorf("pembayaran tunai sebesar %d melebihi sisa tagihan %d", bayar.Jumlah, sisa)
+		}
+		if bayar.Diterima == 0 {
+			bayar.Diterima = bayar.Jumlah
+		}
+		if bayar.Diterima < bayar.Jumlah {
+			return fmt.Errorf("uang tunai diterima %d kurang dari jumlah pembayaran %d", bayar.Diterima, bayar.Jumlah)
+		}
+		bayar.Kembalian = bayar.Diterima - bayar.Jumlah
+		bd.Kembalian = bayar.Kembalian
+		sisa -= bayar.Jumlah
+	}
+
+	if sisa > 0 {
+		return fmt.Errorf("pembayaran kurang %d dari total %d", sisa, bd.Total)
+	}
+	return nil
+}
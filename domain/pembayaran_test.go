@@ -0,0 +1,137 @@
+package domain_test
+
+import (
+	"SIE-SRC/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyPembayaran(t *testing.T) {
+	tests := []struct {
+		name          string
+		total         int
+		pembayaran    []domain.Pembayaran
+		wantErr       string
+		wantJumlah    []int
+		wantDiterima  []int
+		wantKembalian int
+	}{
+		{
+			name:          "tunai saja dengan jumlah kosong",
+			total:         43500,
+			pembayaran:    []domain.Pembayaran{{Metode: domain.MetodeTunai, Diterima: 50000}},
+			wantJumlah:    []int{43500},
+			wantDiterima:  []int{50000},
+			wantKembalian: 6500,
+		},
+		{
+			name:         "tunai uang pas",
+			total:        43500,
+			pembayaran:   []domain.Pembayaran{{Metode: domain.MetodeTunai, Jumlah: 43500}},
+			wantJumlah:   []int{43500},
+			wantDiterima: []int{43500},
+		},
+		{
+			name:  "kartu dan tunai dengan kembalian",
+			total: 100000,
+			pembayaran: []domain.Pembayaran{
+				{Metode: domain.MetodeTunai, Diterima: 50000},
+				{Metode: " Kartu ", Jumlah: 60000},
+			},
+			wantJumlah:    []int{40000, 60000},
+			wantDiterima:  []int{50000, 0},
+			wantKembalian: 10000,
+		},
+		{
+			name:  "non tunai lunas tanpa tunai",
+			total: 100000,
+			pembayaran: []domain.Pembayaran{
+				{Metode: domain.MetodeQRIS, Jumlah: 30000},
+				{Metode: domain.MetodeTransfer, Jumlah: 70000, Diterima: 70000},
+			},
+			wantJumlah:   []int{30000, 70000},
+			wantDiterima: []int{0, 0},
+		},
+		{
+			name:       "non tunai melebihi sisa tagihan",
+			total:      100000,
+			pembayaran: []domain.Pembayaran{{Metode: domain.MetodeKartu, Jumlah: 60000}, {Metode: domain.MetodeEWallet, Jumlah: 50000}},
+			wantErr:    "melebihi sisa tagihan 40000",
+		},
+		{
+			name:       "dua pembayaran tunai",
+			total:      100000,
+			pembayaran: []domain.Pembayaran{{Metode: domain.MetodeTunai, Jumlah: 50000}, {Metode: domain.MetodeTunai, Jumlah: 50000}},
+			wantErr:    "hanya boleh satu kali",
+		},
+		{
+			name:       "kurang bayar non tunai",
+			total:      100000,
+			pembayaran: []domain.Pembayaran{{Metode: domain.MetodeKartu, Jumlah: 50000}},
+			wantErr:    "pembayaran kurang 50000",
+		},
+		{
+			name:       "kurang bayar tunai",
+			total:      100000,
+			pembayaran: []domain.Pembayaran{{Metode: domain.MetodeKartu, Jumlah: 50000}, {Metode: domain.MetodeTunai, Diterima: 30000}},
+			wantErr:    "pembayaran kurang 20000",
+		},
+		{
+			name:       "tunai diterima kurang dari jumlah",
+			total:      100000,
+			pembayaran: []domain.Pembayaran{{Metode: domain.MetodeTunai, Jumlah: 100000, Diterima: 90000}},
+			wantErr:    "kurang dari jumlah pembayaran",
+		},
+		{
+			name:       "tunai tanpa nominal",
+			total:      100000,
+			pembayaran: []domain.Pembayaran{{Metode: domain.MetodeTunai}},
+			wantErr:    "harus lebih dari 0",
+		},
+		{
+			name:       "metode tidak dikenal",
+			total:      100000,
+			pembayaran: []domain.Pembayaran{{Metode: "cek", Jumlah: 100000}},
+			wantErr:    "tidak dikenal",
+		},
+		{
+			name:         "total nol tanpa pembayaran",
+			total:        0,
+			wantJumlah:   []int{},
+			wantDiterima: []int{},
+		},
+		{
+			name:       "total nol dengan pembayaran",
+			total:      0,
+			pembayaran: []domain.Pembayaran{{Metode: domain.MetodeKartu, Jumlah: 1000}},
+			wantErr:    "melebihi sisa tagihan 0",
+		},
+		{
+			name:    "pembayaran kosong",
+			total:   100000,
+			wantErr: "belum diisi",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bd := domain.Penjualan{Total: tt.total, Pembayaran: tt.pembayaran}
+			err := bd.ApplyPembayaran()
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			for i, bayar := range bd.Pembayaran {
+				assert.Equal(t, tt.wantJumlah[i], bayar.Jumlah)
+				assert.Equal(t, tt.wantDiterima[i], bayar.Diterima)
+			}
+			assert.Equal(t, tt.wantKembalian, bd.Kembalian)
+			if len(bd.Pembayaran) > 0 {
+				assert.Equal(t, tt.wantKembalian, bd.Pembayaran[0].Kembalian)
+			}
+		})
+	}
+}
@@ -14,8 +14,26 @@ type ProdukJual struct {
 	IDProduk     string `json:"id_produk" bson:"id_produk"`
 	NamaProduk   string `json:"nama_produk" bson:"nama_produk"`
 	JumlahProduk int    `json:"jumlah_produk" bson:"jumlah_produk"`
+	Satuan       string `json:"satuan,omitempty" bson:"satuan,omitempty"`
+	Konversi     int    `json:"konversi,omitempty" bson:"konversi,omitempty"` // satuan dasar per satuan
 	Harga        int    `json:"harga" bson:"harga"`
 	Subtotal     int    `json:"subtotal" bson:"subtotal"`
+	HargaPokok   int    `json:"harga_pokok" bson:"harga_pokok"` // snapshot HPP per satuan saat transaksi
+	// Tier adalah tier harga yang dipakai untuk Harga, nil jika memakai harga normal
+	Tier *HargaTier `json:"tier,omitempty" bson:"tier,omitempty"`
+	// Diskon adalah potongan promo baris; nilai bersih baris = Subtotal - Diskon
+	Diskon int           `json:"diskon" bson:"diskon"`
+	Promo  *PromoDipakai `json:"promo,omitempty" bson:"promo,omitempty"`
+	// DiskonTransaksi adalah bagian diskon promo transaksi (Penjualan.Diskon) untuk baris ini
+	DiskonTransaksi int `json:"diskon_transaksi,omitempty" bson:"diskon_transaksi,omitempty"`
+	// TarifPajak adalah snapshot tarif PPN (persen) yang berlaku untuk baris ini
+	TarifPajak float64 `json:"tarif_pajak" bson:"tarif_pajak"`
+	// DPP adalah bagian DPP rincian pajak tarifnya untuk baris ini (nilai penjualan tanpa PPN)
+	DPP int `json:"dpp,omitempty" bson:"dpp,omitempty"`
+	// Komponen adalah snapshot komponen paket saat transaksi, dipakai untuk mengembalikan stok
+	Komponen []ProdukKomponen `json:"komponen,omitempty" bson:"komponen,omitempty"`
+	// Batch berisi batch yang terpakai (FEFO) untuk produk dengan tanggal kadaluarsa
+	Batch []BatchConsumption `json:"batch,omitempty" bson:"batch,omitempty"`
 }
 
 type Penjualan struct {
@@ -23,7 +41,26 @@ type Penjualan struct {
 	Produk      []ProdukJual `json:"produk" bson:"produk"`
 	IDPenjualan string       `json:"id_penjualan" bson:"id_penjualan"`
 	Total       int          `json:"total" bson:"total"`
+	Version     int64        `json:"version" bson:"version"`
 	UpdatedAt   time.Time    `json:"updated_at" bson:"updated_at"`
+	// GrupPelanggan menentukan tier harga khusus grup (misal "member", "reseller")
+	GrupPelanggan string `json:"grup_pelanggan,omitempty" bson:"grup_pelanggan,omitempty"`
+	// Subtotal adalah jumlah nilai bersih baris; Subtotal - Diskon (promo transaksi) adalah nilai
+	// sebelum PPN eksklusif. Total adalah grand total yang dibayar pelanggan.
+	Subtotal int           `json:"subtotal" bson:"subtotal"`
+	Diskon   int           `json:"diskon" bson:"diskon"`
+	Promo    *PromoDipakai `json:"promo,omitempty" bson:"promo,omitempty"`
+	// WaktuPromo adalah waktu server saat promo dievaluasi (transaksi pertama kali disimpan).
+	// Edit penjualan mengevaluasi ulang promo pada waktu ini, bukan waktu edit.
+	WaktuPromo time.Time `json:"waktu_promo,omitempty" bson:"waktu_promo,omitempty"`
+	// Pajak dihitung saat transaksi disimpan; ModePajak adalah snapshot pengaturan toko
+	ModePajak string         `json:"mode_pajak,omitempty" bson:"mode_pajak,omitempty"`
+	DPP       int            `json:"dpp" bson:"dpp"`
+	PPN       int            `json:"ppn" bson:"ppn"`
+	Pajak     []PajakRincian `json:"pajak,omitempty" bson:"pajak,omitempty"`
+	// Pembayaran harus menutup Total; Kembalian adalah kembalian tunai
+	Pembayaran []Pembayaran `json:"pembayaran" bson:"pembayaran"`
+	Kembalian  int          `json:"kembalian" bson:"kembalian"`
 }
 
 type PenjualanRepository interface {
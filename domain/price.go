@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Sumber perubahan harga jual yang dicatat di riwayat harga
+const (
+	PriceSourceCreate   = "create"
+	PriceSourceImport   = "import"
+	PriceSourceManual   = "manual"
+	PriceSourceSchedule = "schedule"
+)
+
+// Status jadwal perubahan harga
+const (
+	PriceScheduleStatusPending   = "pending"
+	PriceScheduleStatusApplied   = "applied"
+	PriceScheduleStatusCancelled = "cancelled"
+	PriceScheduleStatusFailed    = "failed" // tidak bisa diterapkan, alasan di Keterangan
+)
+
+// PriceRef menjelaskan asal sebuah perubahan harga jual
+type PriceRef struct {
+	Source string
+	RefID  string
+	User   string
+}
+
+// PriceHistory adalah satu perubahan harga jual produk
+type PriceHistory struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	IDProduk  string             `json:"id_produk" bson:"id_produk"`
+	HargaLama int                `json:"harga_lama" bson:"harga_lama"`
+	HargaBaru int                `json:"harga_baru" bson:"harga_baru"`
+	Source    string             `json:"source" bson:"source"`
+	RefID     string             `json:"ref_id,omitempty" bson:"ref_id,omitempty"`
+	User      string             `json:"user" bson:"user"`
+	Timestamp time.Time          `json:"timestamp" bson:"timestamp"`
+}
+
+// PriceSchedule adalah perubahan harga yang diterapkan otomatis pada waktu BerlakuMulai
+type PriceSchedule struct {
+	ID           primitive.ObjectID `json:"id_jadwal" bson:"_id,omitempty"`
+	IDProduk     string             `json:"id_produk" bson:"id_produk"`
+	HargaBaru    int                `json:"harga_baru" bson:"harga_baru"`
+	BerlakuMulai time.Time          `json:"berlaku_mulai" bson:"berlaku_mulai"`
+	Status       string             `json:"status" bson:"status"`
+	DibuatOleh   string             `json:"dibuat_oleh" bson:"dibuat_oleh"`
+	CreatedAt    time.Time          `json:"created_at" bson:"created_at"`
+	AppliedAt    *time.Time         `json:"applied_at,omitempty" bson:"applied_at,omitempty"`
+	Keterangan   string             `json:"keterangan,omitempty" bson:"keterangan,omitempty"`
+}
+
+// PriceTimeline menggabungkan riwayat harga dan jadwal yang belum berlaku
+type PriceTimeline struct {
+	IDProduk     string          `json:"id_produk"`
+	HargaSaatIni int             `json:"harga_saat_ini"`
+	Riwayat      []PriceHistory  `json:"riwayat"`
+	Terjadwal    []PriceSchedule `json:"terjadwal"`
+}
+
+type PriceHistoryRepository interface {
+	Record(ctx context.Context, history ...PriceHistory) error
+	GetByProduk(ctx context.Context, idProduk string) ([]PriceHistory, error)
+}
+
+type PriceScheduleRepository interface {
+	Create(ctx context.Context, bd *PriceSchedule) (PriceSchedule, error)
+	GetPendingByProduk(ctx context.Context, idProduk string) ([]PriceSchedule, error)
+	Cancel(ctx context.Context, id string) error
+	GetAllPending(ctx context.Context) ([]PriceSchedule, error)
+	ApplyDue(ctx context.Context, now time.Time) (int, error)
+}
+
+type PriceUseCase interface {
+	GetTimeline(ctx context.Context, idProduk string) (PriceTimeline, error)
+	CreateSchedule(ctx context.Context, bd *PriceSchedule) (PriceSchedule, error)
+	GetPendingSchedules(ctx context.Context) ([]PriceSchedule, error)
+	CancelSchedule(ctx context.Context, id string) error
+	ApplyDueSchedules(ctx context.Context) (int, error)
+}
@@ -2,40 +2,100 @@ package domain
 
 import (
 	"context"
+	"fmt"
 	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type Produk struct {
-	IDProduk    string     `json:"id_produk" bson:"_id"`
-	NamaProduk  string     `json:"nama_produk" bson:"nama_produk"`
-	Kategori    string     `json:"kategori" bson:"kategori"`
-	SubKategori string     `json:"sub_kategori" bson:"sub_kategori"`
-	KodeProduk  string     `json:"barcode_produk" bson:"barcode_produk"`
-	Harga       int        `json:"harga" bson:"harga"`
-	Stok        int        `json:"stok_barang" bson:"stok_barang"`
-	UpdatedAt   time.Time  `json:"updated_at" bson:"updated_at"`
-	IsDeleted   *time.Time `json:"is_deleted" bson:"is_deleted"`
+	IDProduk     string            `json:"id_produk" bson:"_id"`
+	NamaProduk   string            `json:"nama_produk" bson:"nama_produk"`
+	Kategori     string            `json:"kategori" bson:"kategori"`
+	SubKategori  string            `json:"sub_kategori" bson:"sub_kategori"`
+	KodeProduk   string            `json:"barcode_produk" bson:"barcode_produk"`
+	Harga        int               `json:"harga" bson:"harga"`
+	HargaPokok   int               `json:"harga_pokok" bson:"harga_pokok"` // HPP rata-rata bergerak dari penerimaan barang
+	Stok         int               `json:"stok_barang" bson:"stok_barang"` // selalu dalam satuan dasar
+	SatuanDasar  string            `json:"satuan_dasar" bson:"satuan_dasar"`
+	Satuan       []ProdukSatuan    `json:"satuan,omitempty" bson:"satuan,omitempty"`
+	HargaTier    []HargaTier       `json:"harga_tier,omitempty" bson:"harga_tier,omitempty"`   // harga grosir & grup pelanggan
+	TarifPajak   *float64          `json:"tarif_pajak,omitempty" bson:"tarif_pajak,omitempty"` // kosong = ikut kategori
+	IDInduk      string            `json:"id_induk,omitempty" bson:"id_induk,omitempty"`
+	Atribut      map[string]string `json:"atribut,omitempty" bson:"atribut,omitempty"`   // nilai varian, misal {"ukuran": "L"}
+	Komponen     []ProdukKomponen  `json:"komponen,omitempty" bson:"komponen,omitempty"` // stok paket dihitung dari komponen
+	Gambar       []ProdukGambar    `json:"gambar,omitempty" bson:"gambar,omitempty"`     // dikelola lewat endpoint gambar
+	IDSupplier   string            `json:"id_supplier" bson:"id_supplier"`
+	ReorderPoint int               `json:"reorder_point" bson:"reorder_point"`
+	ReorderQty   int               `json:"reorder_qty" bson:"reorder_qty"`
+	Version      int64             `json:"version" bson:"version"`
+	UpdatedAt    time.Time         `json:"updated_at" bson:"updated_at"`
+	IsDeleted    *time.Time        `json:"is_deleted" bson:"is_deleted"`
+}
+
+// InsufficientStockError dikembalikan saat stok produk tidak cukup untuk dikurangi
+type InsufficientStockError struct {
+	IDProduk   string `json:"id_produk"`
+	NamaProduk string `json:"nama_produk"`
+	Tersedia   int    `json:"tersedia"`
+	Diminta    int    `json:"diminta"`
+}
+
+func (e *InsufficientStockError) Error() string {
+	return fmt.Sprintf("stok produk %s tidak mencukupi (tersedia: %d, diminta: %d)",
+		e.NamaProduk, e.Tersedia, e.Diminta)
+}
+
+// ProdukFilter membatasi daftar produk; field kosong berarti tidak difilter
+type ProdukFilter struct {
+	IDSupplier string
+	IDInduk    string
+	// LowStock hanya menampilkan produk dengan stok <= reorder point (reorder point > 0)
+	LowStock bool
+}
+
+// ProdukPurgeResult merangkum hasil penghapusan permanen produk di trash
+type ProdukPurgeResult struct {
+	Purged  int64    `json:"purged"`
+	Skipped []string `json:"skipped"`
 }
 
 type ProdukRepository interface {
 	CreateProduk(ctx context.Context, bd *Produk) (Produk, error)
 	GetAllProduk(ctx context.Context) ([]Produk, error)
+	FindProduk(ctx context.Context, filter ProdukFilter) ([]Produk, error)
 	GetProdukById(ctx context.Context, id string) (*Produk, error)
 	GetProdukByName(ctx context.Context, nama string) (*Produk, error)
 	UpdateProduk(ctx context.Context, bd *Produk) error
 	DeleteProduk(ctx context.Context, id string) error
-	DecreaseProdukStock(ctx context.Context, id string, kuantitas int) error
-	IncreaseProdukStock(ctx context.Context, id string, kuantitas int) error
+	DecreaseProdukStock(ctx context.Context, id string, kuantitas int, ref StockRef) error
+	IncreaseProdukStock(ctx context.Context, id string, kuantitas int, ref StockRef) error
+	AdjustProdukStock(ctx context.Context, id string, delta int, ref StockRef) error
+	SetHargaPokok(ctx context.Context, id string, hargaPokok int) error
+	SetHarga(ctx context.Context, id string, harga int, ref PriceRef) error
+	AddGambar(ctx context.Context, id string, gambar ProdukGambar) error
+	RemoveGambar(ctx context.Context, id string, idGambar primitive.ObjectID) (*ProdukGambar, error)
+	ReorderGambar(ctx context.Context, id string, ids []primitive.ObjectID) ([]ProdukGambar, error)
+	GenerateBarcodes(ctx context.Context) ([]ProdukBarcode, error)
+	GetStockMovements(ctx context.Context, id string, from, to time.Time) ([]StockMovement, error)
 	ImportData(ctx context.Context, produkList []Produk) error
+	GetDeletedProduk(ctx context.Context) ([]Produk, error)
+	RestoreProduk(ctx context.Context, ids []string) (int64, error)
+	PurgeDeletedProduk(ctx context.Context, before time.Time) (ProdukPurgeResult, error)
 	GenerateNextID(ctx context.Context) (string, error)
 }
 
 type ProdukUseCase interface {
 	CreateProduk(ctx context.Context, bd *Produk) (Produk, error)
 	GetAllProduk(ctx context.Context) ([]Produk, error)
+	FindProduk(ctx context.Context, filter ProdukFilter) ([]Produk, error)
 	GetProdukById(ctx context.Context, id string) (*Produk, error)
 	GetProdukByName(ctx context.Context, nama string) (*Produk, error)
 	UpdateProduk(ctx context.Context, bd *Produk) error
 	DeleteProduk(ctx context.Context, id string) error
 	ImportData(ctx context.Context, produkList []Produk) error
+	GetDeletedProduk(ctx context.Context) ([]Produk, error)
+	RestoreProduk(ctx context.Context, ids []string) (int64, error)
+	PurgeDeletedProduk(ctx context.Context, before time.Time) (ProdukPurgeResult, error)
+	GetStockMovements(ctx context.Context, id string, from, to time.Time) ([]StockMovement, error)
 }
@@ -0,0 +1,252 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Tipe promo
+const (
+	PromoTipePersen  = "persen"          // diskon persen dari subtotal
+	PromoTipeNominal = "nominal"         // diskon rupiah per item, atau per transaksi untuk cakupan transaksi
+	PromoTipeBeliXY  = "beli_x_gratis_y" // setiap beli X item gratis Y item yang sama
+)
+
+// Cakupan promo
+const (
+	PromoCakupanProduk    = "produk"
+	PromoCakupanKategori  = "kategori"
+	PromoCakupanTransaksi = "transaksi"
+)
+
+// Promo adalah aturan diskon. Promo produk/kategori dihitung per baris penjualan,
+// promo transaksi dihitung dari subtotal setelah diskon baris.
+type Promo struct {
+	IDPromo string `json:"id_promo" bson:"_id"`
+	Nama    string `json:"nama" bson:"nama"`
+	Tipe    string `json:"tipe" bson:"tipe"`
+	Cakupan string `json:"cakupan" bson:"cakupan"`
+	// Target berisi ID produk (cakupan produk) atau nama kategori/sub kategori (cakupan kategori)
+	Target       []string `json:"target,omitempty" bson:"target,omitempty"`
+	Nilai        int      `json:"nilai" bson:"nilai"` // persen (1-100) atau nominal rupiah
+	BeliJumlah   int      `json:"beli_jumlah,omitempty" bson:"beli_jumlah,omitempty"`
+	GratisJumlah int      `json:"gratis_jumlah,omitempty" bson:"gratis_jumlah,omitempty"`
+	MinBelanja   int      `json:"min_belanja,omitempty" bson:"min_belanja,omitempty"` // hanya untuk cakupan transaksi
+	// Periode berlaku; kosong berarti tidak dibatasi
+	MulaiTanggal   *time.Time `json:"mulai_tanggal,omitempty" bson:"mulai_tanggal,omitempty"`
+	SelesaiTanggal *time.Time `json:"selesai_tanggal,omitempty" bson:"selesai_tanggal,omitempty"`
+	// Happy hour: jam harian (HH:MM, waktu lokal) dan hari (0 = Minggu); kosong berarti sepanjang hari
+	JamMulai   string    `json:"jam_mulai,omitempty" bson:"jam_mulai,omitempty"`
+	JamSelesai string    `json:"jam_selesai,omitempty" bson:"jam_selesai,omitempty"`
+	Hari       []int     `json:"hari,omitempty" bson:"hari,omitempty"`
+	Aktif      bool      `json:"aktif" bson:"aktif"`
+	UpdatedAt  time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// PromoDipakai mencatat promo yang diterapkan pada baris atau header penjualan
+type PromoDipakai struct {
+	IDPromo string `json:"id_promo" bson:"id_promo"`
+	Nama    string `json:"nama" bson:"nama"`
+}
+
+// parseJam membaca jam HH:MM menjadi menit sejak tengah malam
+func parseJam(value string) (int, error) {
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("format jam %s tidak valid, gunakan HH:MM", value)
+	}
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
+
+// Validate merapikan dan memvalidasi aturan promo
+func (p *Promo) Validate() error {
+	p.Nama = strings.TrimSpace(p.Nama)
+	p.Tipe = strings.ToLower(strings.TrimSpace(p.Tipe))
+	p.Cakupan = strings.ToLower(strings.TrimSpace(p.Cakupan))
+
+	if p.Nama == "" {
+		return fmt.Errorf("nama promo tidak boleh kosong")
+	}
+
+	switch p.Cakupan {
+	case PromoCakupanProduk, PromoCakupanKategori:
+		target := make([]string, 0, len(p.Target))
+		for _, t := range p.Target {
+			if t = strings.TrimSpace(t); t != "" {
+				target = append(target, t)
+			}
+		}
+		if len(target) == 0 {
+			return fmt.Errorf("promo cakupan %s harus memiliki target", p.Cakupan)
+		}
+		p.Target = target
+	case PromoCakupanTransaksi:
+		p.Target = nil
+	default:
+		return fmt.Errorf("cakupan promo %s tidak dikenal", p.Cakupan)
+	}
+
+	switch p.Tipe {
+	case PromoTipePersen:
+		if p.Nilai < 1 || p.Nilai > 100 {
+			return fmt.Errorf("diskon persen harus antara 1 dan 100")
+		}
+	case PromoTipeNominal:
+		if p.Nilai <= 0 {
+			return fmt.Errorf("diskon nominal harus lebih dari 0")
+		}
+	case PromoTipeBeliXY:
+		if p.Cakupan == PromoCakupanTransaksi {
+			return fmt.Errorf("promo beli X gratis Y hanya untuk cakupan produk atau kategori")
+		}
+		if p.BeliJumlah < 1 || p.GratisJumlah < 1 {
+			return fmt.Errorf("jumlah beli dan gratis harus lebih dari 0")
+		}
+		p.Nilai = 0
+	default:
+		return fmt.Errorf("tipe promo %s tidak dikenal", p.Tipe)
+	}
+
+	if p.MinBelanja < 0 {
+		return fmt.Errorf("minimal belanja tidak boleh negatif")
+	}
+	if p.MulaiTanggal != nil && p.SelesaiTanggal != nil && !p.SelesaiTanggal.After(*p.MulaiTanggal) {
+		return fmt.Errorf("tanggal selesai promo harus setelah tanggal mulai")
+	}
+
+	if (p.JamMulai == "") != (p.JamSelesai == "") {
+		return fmt.Errorf("jam mulai dan jam selesai happy hour harus diisi keduanya")
+	}
+	if p.JamMulai != "" {
+		mulai, err := parseJam(p.JamMulai)
+		if err != nil {
+			return err
+		}
+		selesai, err := parseJam(p.JamSelesai)
+		if err != nil {
+			return err
+		}
+		if mulai == selesai {
+			return fmt.Errorf("jam mulai dan jam selesai happy hour tidak boleh sama")
+		}
+	}
+	for _, hari := range p.Hari {
+		if hari < 0 || hari > 6 {
+			return fmt.Errorf("hari promo harus antara 0 (Minggu) dan 6 (Sabtu)")
+		}
+	}
+
+	return nil
+}
+
+// BerlakuPada memeriksa apakah promo aktif pada waktu transaksi t (waktu lokal).
+// Happy hour yang melewati tengah malam (misal 22:00-02:00) didukung.
+func (p *Promo) BerlakuPada(t time.Time) bool {
+	if !p.Aktif {
+		return false
+	}
+	if p.MulaiTanggal != nil && t.Before(*p.MulaiTanggal) {
+		return false
+	}
+	if p.SelesaiTanggal != nil && !t.Before(*p.SelesaiTanggal) {
+		return false
+	}
+
+	local := t.In(time.Local)
+	if len(p.Hari) > 0 {
+		found := false
+		for _, hari := range p.Hari {
+			if time.Weekday(hari) == local.Weekday() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if p.JamMulai != "" {
+		mulai, errMulai := parseJam(p.JamMulai)
+		selesai, errSelesai := parseJam(p.JamSelesai)
+		if errMulai != nil || errSelesai != nil {
+			return false
+		}
+		menit := local.Hour()*60 + local.Minute()
+		if mulai < selesai {
+			return menit >= mulai && menit < selesai
+		}
+		return menit >= mulai || menit < selesai
+	}
+
+	return true
+}
+
+// CocokProduk memeriksa apakah promo produk/kategori berlaku untuk produk tersebut
+func (p *Promo) CocokProduk(produk *Produk) bool {
+	for _, target := range p.Target {
+		switch p.Cakupan {
+		case PromoCakupanProduk:
+			if target == produk.IDProduk {
+				return true
+			}
+		case PromoCakupanKategori:
+			if strings.EqualFold(target, produk.Kategori) || strings.EqualFold(target, produk.SubKategori) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// DiskonBaris menghitung diskon promo produk/kategori untuk satu baris penjualan.
+// Hasil dibulatkan ke bawah dan tidak melebihi subtotal baris.
+func (p *Promo) DiskonBaris(line ProdukJual) int {
+	diskon := 0
+	switch p.Tipe {
+	case PromoTipePersen:
+		diskon = line.Subtotal * p.Nilai / 100
+	case PromoTipeNominal:
+		diskon = p.Nilai * line.JumlahProduk
+	case PromoTipeBeliXY:
+		paket := p.BeliJumlah + p.GratisJumlah
+		diskon = line.JumlahProduk / paket * p.GratisJumlah * line.Harga
+	}
+	return min(diskon, line.Subtotal)
+}
+
+// DiskonTransaksi menghitung diskon promo transaksi dari subtotal setelah diskon baris
+func (p *Promo) DiskonTransaksi(subtotal int) int {
+	if subtotal < p.MinBelanja {
+		return 0
+	}
+
+	diskon := 0
+	switch p.Tipe {
+	case PromoTipePersen:
+		diskon = subtotal * p.Nilai / 100
+	case PromoTipeNominal:
+		diskon = p.Nilai
+	}
+	return min(diskon, subtotal)
+}
+
+type PromoRepository interface {
+	Create(ctx context.Context, bd *Promo) (Promo, error)
+	GetAll(ctx context.Context) ([]Promo, error)
+	GetAktif(ctx context.Context) ([]Promo, error)
+	GetByID(ctx context.Context, id string) (*Promo, error)
+	Update(ctx context.Context, bd *Promo) error
+	Delete(ctx context.Context, id string) error
+}
+
+type PromoUseCase interface {
+	Create(ctx context.Context, bd *Promo) (Promo, error)
+	GetAll(ctx context.Context) ([]Promo, error)
+	GetBerlaku(ctx context.Context, at time.Time) ([]Promo, error)
+	GetByID(ctx context.Context, id string) (*Promo, error)
+	Update(ctx context.Context, bd *Promo) error
+	Delete(ctx context.Context, id string) error
+}
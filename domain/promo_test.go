@@ -0,0 +1,142 @@
+package domain_test
+
+import (
+	"SIE-SRC/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPromoDiskonBaris(t *testing.T) {
+	tests := []struct {
+		name  string
+		promo domain.Promo
+		line  domain.ProdukJual
+		want  int
+	}{
+		{
+			name:  "persen dari subtotal",
+			promo: domain.Promo{Tipe: domain.PromoTipePersen, Nilai: 10},
+			line:  domain.ProdukJual{JumlahProduk: 5, Harga: 5000, Subtotal: 25000},
+			want:  2500,
+		},
+		{
+			name:  "persen dibulatkan ke bawah",
+			promo: domain.Promo{Tipe: domain.PromoTipePersen, Nilai: 10},
+			line:  domain.ProdukJual{JumlahProduk: 1, Harga: 999, Subtotal: 999},
+			want:  99,
+		},
+		{
+			name:  "nominal per item",
+			promo: domain.Promo{Tipe: domain.PromoTipeNominal, Nilai: 500},
+			line:  domain.ProdukJual{JumlahProduk: 3, Harga: 4000, Subtotal: 12000},
+			want:  1500,
+		},
+		{
+			name:  "nominal tidak melebihi subtotal",
+			promo: domain.Promo{Tipe: domain.PromoTipeNominal, Nilai: 5000},
+			line:  domain.ProdukJual{JumlahProduk: 2, Harga: 3000, Subtotal: 6000},
+			want:  6000,
+		},
+		{
+			name:  "beli 2 gratis 1",
+			promo: domain.Promo{Tipe: domain.PromoTipeBeliXY, BeliJumlah: 2, GratisJumlah: 1},
+			line:  domain.ProdukJual{JumlahProduk: 7, Harga: 1000, Subtotal: 7000},
+			want:  2000,
+		},
+		{
+			name:  "beli X gratis Y belum mencapai paket",
+			promo: domain.Promo{Tipe: domain.PromoTipeBeliXY, BeliJumlah: 2, GratisJumlah: 1},
+			line:  domain.ProdukJual{JumlahProduk: 2, Harga: 1000, Subtotal: 2000},
+			want:  0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.promo.DiskonBaris(tt.line))
+		})
+	}
+}
+
+func TestPromoDiskonTransaksi(t *testing.T) {
+	tests := []struct {
+		name     string
+		promo    domain.Promo
+		subtotal int
+		want     int
+	}{
+		{name: "di bawah minimal belanja", promo: domain.Promo{Tipe: domain.PromoTipePersen, Nilai: 10, MinBelanja: 100000}, subtotal: 99999, want: 0},
+		{name: "persen tepat di minimal belanja", promo: domain.Promo{Tipe: domain.PromoTipePersen, Nilai: 10, MinBelanja: 100000}, subtotal: 100000, want: 10000},
+		{name: "persen dibulatkan ke bawah", promo: domain.Promo{Tipe: domain.PromoTipePersen, Nilai: 15}, subtotal: 12345, want: 1851},
+		{name: "nominal", promo: domain.Promo{Tipe: domain.PromoTipeNominal, Nilai: 20000}, subtotal: 150000, want: 20000},
+		{name: "nominal tidak melebihi subtotal", promo: domain.Promo{Tipe: domain.PromoTipeNominal, Nilai: 20000}, subtotal: 15000, want: 15000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.promo.DiskonTransaksi(tt.subtotal))
+		})
+	}
+}
+
+func TestPromoBerlakuPada(t *testing.T) {
+	// 16 Oktober 2026 adalah hari Jumat
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, time.October, day, hour, minute, 0, 0, time.Local)
+	}
+	mulai := at(1, 0, 0)
+	selesai := at(31, 0, 0)
+
+	tests := []struct {
+		name  string
+		promo domain.Promo
+		t     time.Time
+		want  bool
+	}{
+		{name: "tidak aktif", promo: domain.Promo{Aktif: false}, t: at(16, 12, 0), want: false},
+		{name: "tanpa batas", promo: domain.Promo{Aktif: true}, t: at(16, 12, 0), want: true},
+		{name: "sebelum periode", promo: domain.Promo{Aktif: true, MulaiTanggal: &mulai}, t: at(1, 0, 0).Add(-time.Minute), want: false},
+		{name: "awal periode", promo: domain.Promo{Aktif: true, MulaiTanggal: &mulai}, t: at(1, 0, 0), want: true},
+		{name: "tanggal selesai eksklusif", promo: domain.Promo{Aktif: true, SelesaiTanggal: &selesai}, t: at(31, 0, 0), want: false},
+		{name: "hari sesuai", promo: domain.Promo{Aktif: true, Hari: []int{5}}, t: at(16, 12, 0), want: true},
+		{name: "hari tidak sesuai", promo: domain.Promo{Aktif: true, Hari: []int{5}}, t: at(17, 12, 0), want: false},
+		{name: "happy hour siang", promo: domain.Promo{Aktif: true, JamMulai: "15:00", JamSelesai: "17:00"}, t: at(16, 16, 0), want: true},
+		{name: "happy hour jam selesai eksklusif", promo: domain.Promo{Aktif: true, JamMulai: "15:00", JamSelesai: "17:00"}, t: at(16, 17, 0), want: false},
+		{name: "happy hour sebelum jam mulai", promo: domain.Promo{Aktif: true, JamMulai: "15:00", JamSelesai: "17:00"}, t: at(16, 14, 59), want: false},
+		{name: "lewat tengah malam sebelum 00:00", promo: domain.Promo{Aktif: true, JamMulai: "22:00", JamSelesai: "02:00"}, t: at(16, 23, 30), want: true},
+		{name: "lewat tengah malam setelah 00:00", promo: domain.Promo{Aktif: true, JamMulai: "22:00", JamSelesai: "02:00"}, t: at(17, 1, 59), want: true},
+		{name: "lewat tengah malam jam selesai", promo: domain.Promo{Aktif: true, JamMulai: "22:00", JamSelesai: "02:00"}, t: at(17, 2, 0), want: false},
+		{name: "lewat tengah malam sebelum jam mulai", promo: domain.Promo{Aktif: true, JamMulai: "22:00", JamSelesai: "02:00"}, t: at(16, 21, 59), want: false},
+		{name: "lewat tengah malam siang hari", promo: domain.Promo{Aktif: true, JamMulai: "22:00", JamSelesai: "02:00"}, t: at(16, 12, 0), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.promo.BerlakuPada(tt.t))
+		})
+	}
+}
+
+func TestBagiProporsional(t *testing.T) {
+	tests := []struct {
+		name   string
+		jumlah int
+		bobot  []int
+		want   []int
+	}{
+		{name: "tanpa bobot", jumlah: 100, bobot: nil, want: []int{}},
+		{name: "satu bobot", jumlah: 100, bobot: []int{5000}, want: []int{100}},
+		{name: "proporsional", jumlah: 1000, bobot: []int{30000, 70000}, want: []int{300, 700}},
+		{name: "sisa masuk ke terakhir", jumlah: 100, bobot: []int{1, 1, 1}, want: []int{33, 33, 34}},
+		{name: "bobot nol", jumlah: 100, bobot: []int{0, 0}, want: []int{0, 100}},
+		{name: "jumlah nol", jumlah: 0, bobot: []int{1000, 2000}, want: []int{0, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, domain.BagiProporsional(tt.jumlah, tt.bobot))
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Status purchase order
+const (
+	POStatusOpen      = "open"
+	POStatusPartial   = "partial"
+	POStatusReceived  = "received"
+	POStatusCancelled = "cancelled"
+)
+
+type PurchaseOrderItem struct {
+	IDProduk       string `json:"id_produk" bson:"id_produk"`
+	NamaProduk     string `json:"nama_produk" bson:"nama_produk"`
+	Satuan         string `json:"satuan" bson:"satuan"`
+	Konversi       int    `json:"konversi" bson:"konversi"` // satuan dasar per satuan pesanan
+	JumlahPesan    int    `json:"jumlah_pesan" bson:"jumlah_pesan"`
+	JumlahDiterima int    `json:"jumlah_diterima" bson:"jumlah_diterima"`
+	HargaBeli      int    `json:"harga_beli" bson:"harga_beli"`
+	Subtotal       int    `json:"subtotal" bson:"subtotal"`
+}
+
+type PurchaseOrder struct {
+	IDPO         string              `json:"id_po" bson:"_id"`
+	IDSupplier   string              `json:"id_supplier" bson:"id_supplier"`
+	NamaSupplier string              `json:"nama_supplier" bson:"nama_supplier"`
+	Tanggal      time.Time           `json:"tanggal" bson:"tanggal"`
+	Status       string              `json:"status" bson:"status"`
+	Items        []PurchaseOrderItem `json:"items" bson:"items"`
+	Total        int                 `json:"total" bson:"total"`
+	DibuatOleh   string              `json:"dibuat_oleh" bson:"dibuat_oleh"`
+	UpdatedAt    time.Time           `json:"updated_at" bson:"updated_at"`
+}
+
+type GoodsReceiptItem struct {
+	IDProduk   string `json:"id_produk" bson:"id_produk"`
+	NamaProduk string `json:"nama_produk" bson:"nama_produk"`
+	Jumlah     int    `json:"jumlah" bson:"jumlah"` // dalam satuan baris PO
+	Satuan     string `json:"satuan" bson:"satuan"`
+	HargaBeli  int    `json:"harga_beli" bson:"harga_beli"`
+	Subtotal   int    `json:"subtotal" bson:"subtotal"`
+	// NoBatch dan TanggalKadaluarsa diisi untuk barang yang memiliki masa kadaluarsa
+	NoBatch           string     `json:"no_batch,omitempty" bson:"no_batch,omitempty"`
+	TanggalKadaluarsa *time.Time `json:"tanggal_kadaluarsa,omitempty" bson:"tanggal_kadaluarsa,omitempty"`
+}
+
+// GoodsReceipt mencatat penerimaan barang (penuh atau sebagian) atas sebuah purchase order
+type GoodsReceipt struct {
+	IDPenerimaan string             `json:"id_penerimaan" bson:"_id"`
+	IDPO         string             `json:"id_po" bson:"id_po"`
+	IDSupplier   string             `json:"id_supplier" bson:"id_supplier"`
+	Tanggal      time.Time          `json:"tanggal" bson:"tanggal"`
+	Items        []GoodsReceiptItem `json:"items" bson:"items"`
+	Total        int                `json:"total" bson:"total"`
+	DiterimaOleh string             `json:"diterima_oleh" bson:"diterima_oleh"`
+}
+
+type PurchaseRepository interface {
+	CreatePO(ctx context.Context, bd *PurchaseOrder) (PurchaseOrder, error)
+	GetAllPO(ctx context.Context) ([]PurchaseOrder, error)
+	GetPOByID(ctx context.Context, id string) (*PurchaseOrder, error)
+	CancelPO(ctx context.Context, id string) error
+	Receive(ctx context.Context, idPO string, bd *GoodsReceipt) (GoodsReceipt, error)
+	GetAllReceipt(ctx context.Context) ([]GoodsReceipt, error)
+	GetReceiptByID(ctx context.Context, id string) (*GoodsReceipt, error)
+}
+
+type PurchaseUseCase interface {
+	CreatePO(ctx context.Context, bd *PurchaseOrder) (PurchaseOrder, error)
+	GetAllPO(ctx context.Context) ([]PurchaseOrder, error)
+	GetPOByID(ctx context.Context, id string) (*PurchaseOrder, error)
+	CancelPO(ctx context.Context, id string) error
+	Receive(ctx context.Context, idPO string, bd *GoodsReceipt) (GoodsReceipt, error)
+	GetAllReceipt(ctx context.Context) ([]GoodsReceipt, error)
+	GetReceiptByID(ctx context.Context, id string) (*GoodsReceipt, error)
+}
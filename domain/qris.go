@@ -0,0 +1,154 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// QRISMerchant adalah data merchant yang dicetak di payload QRIS
+type QRISMerchant struct {
+	Nama       string // maksimal 25 karakter
+	Kota       string // maksimal 15 karakter
+	KodePos    string
+	MCC        string // Merchant Category Code, 4 digit
+	NMID       string // National Merchant ID dari penerbit QRIS
+	Kriteria   string // UMI, UKE, UME, atau UBE
+	GUI        string // Global Unique Identifier PJSP, misal ID.CO.BANKXYZ.WWW
+	MerchantID string // ID merchant di PJSP (opsional)
+	PAN        string // Merchant PAN dari PJSP (opsional)
+}
+
+// QRISPayload adalah payload QRIS dinamis untuk satu penjualan
+type QRISPayload struct {
+	IDPenjualan string `json:"id_penjualan"`
+	Jumlah      int    `json:"jumlah"`
+	Payload     string `json:"payload"`
+}
+
+// Kriteria merchant QRIS: usaha mikro, kecil, menengah, dan besar
+const (
+	QRISKriteriaMikro    = "UMI"
+	QRISKriteriaKecil    = "UKE"
+	QRISKriteriaMenengah = "UME"
+	QRISKriteriaBesar    = "UBE"
+)
+
+// qrisWriter menulis data object EMVCo dan mencatat error pertama, sehingga payload
+// dapat disusun berurutan lalu diperiksa sekali di akhir
+type qrisWriter struct {
+	err error
+}
+
+// tlv menulis satu data object EMVCo: ID 2 digit, panjang 2 digit, lalu nilai.
+// Panjang hanya 2 digit sehingga nilai lebih dari 99 karakter ditolak.
+func (w *qrisWriter) tlv(id, value string) string {
+	if len(value) > 99 && w.err == nil {
+		w.err = fmt.Errorf("nilai tag %s QRIS terlalu panjang (%d karakter, maksimal 99)", id, len(value))
+	}
+	return fmt.Sprintf("%s%02d%s", id, len(value), value)
+}
+
+// truncate memotong nilai ke panjang maksimum yang diizinkan EMVCo
+func truncate(value string, max int) string {
+	value = strings.TrimSpace(value)
+	if len(value) > max {
+		return value[:max]
+	}
+	return value
+}
+
+// CRC16CCITT menghitung checksum CRC-16/CCITT-FALSE (poly 0x1021, init 0xFFFF) untuk tag 63
+func CRC16CCITT(data string) uint16 {
+	crc := uint16(0xFFFF)
+	for i := 0; i < len(data); i++ {
+		crc ^= uint16(data[i]) << 8
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// Validate memastikan data merchant wajib sudah diisi
+func (m QRISMerchant) Validate() error {
+	if m.Nama == "" || m.Kota == "" || m.NMID == "" {
+		return fmt.Errorf("QRIS belum dikonfigurasi: nama merchant, kota, dan NMID wajib diisi")
+	}
+	if len(m.MCC) != 4 {
+		return fmt.Errorf("MCC QRIS harus 4 digit")
+	}
+	if _, err := strconv.Atoi(m.MCC); err != nil {
+		return fmt.Errorf("MCC QRIS harus 4 digit")
+	}
+	switch m.Kriteria {
+	case QRISKriteriaMikro, QRISKriteriaKecil, QRISKriteriaMenengah, QRISKriteriaBesar:
+	default:
+		return fmt.Errorf("kriteria merchant QRIS %q tidak valid, gunakan UMI, UKE, UME, atau UBE", m.Kriteria)
+	}
+	return nil
+}
+
+// BuildQRISPayload menyusun payload QRIS dinamis (EMVCo Merchant Presented Mode)
+// dengan nominal transaksi dan nomor tagihan, diakhiri CRC16 pada tag 63.
+func BuildQRISPayload(m QRISMerchant, jumlah int, billNumber string) (string, error) {
+	if err := m.Validate(); err != nil {
+		return "", err
+	}
+	if jumlah <= 0 {
+		return "", fmt.Errorf("nominal QRIS harus lebih dari 0")
+	}
+
+	var w qrisWriter
+	var b strings.Builder
+	b.WriteString(w.tlv("00", "01"))
+	b.WriteString(w.tlv("01", "12")) // 12 = dinamis, hanya untuk satu transaksi
+
+	// Merchant Account Information PJSP (tag 26) bila tersedia
+	if m.GUI != "" {
+		account := w.tlv("00", m.GUI)
+		if m.PAN != "" {
+			account += w.tlv("01", m.PAN)
+		}
+		if m.MerchantID != "" {
+			account += w.tlv("02", m.MerchantID)
+		}
+		account += w.tlv("03", m.Kriteria)
+		b.WriteString(w.tlv("26", account))
+	}
+
+	// Domestic Central Repository QRIS (tag 51) berisi NMID
+	b.WriteString(w.tlv("51", w.tlv("00", "ID.CO.QRIS.WWW")+w.tlv("02", m.NMID)+w.tlv("03", m.Kriteria)))
+
+	b.WriteString(w.tlv("52", m.MCC))
+	b.WriteString(w.tlv("53", "360")) // IDR
+	b.WriteString(w.tlv("54", strconv.Itoa(jumlah)))
+	b.WriteString(w.tlv("58", "ID"))
+	b.WriteString(w.tlv("59", truncate(m.Nama, 25)))
+	b.WriteString(w.tlv("60", truncate(m.Kota, 15)))
+	if m.KodePos != "" {
+		b.WriteString(w.tlv("61", truncate(m.KodePos, 10)))
+	}
+	if billNumber != "" {
+		b.WriteString(w.tlv("62", w.tlv("01", truncate(billNumber, 25))))
+	}
+
+	if w.err != nil {
+		return "", w.err
+	}
+
+	// CRC dihitung dari seluruh payload termasuk ID dan panjang tag 63
+	b.WriteString("6304")
+	payload := b.String()
+	return payload + fmt.Sprintf("%04X", CRC16CCITT(payload)), nil
+}
+
+type QRISUseCase interface {
+	GetPayload(ctx context.Context, idPenjualan string) (QRISPayload, error)
+	GetImage(ctx context.Context, idPenjualan string, size int) ([]byte, error)
+}
@@ -0,0 +1,148 @@
+package domain_test
+
+import (
+	"SIE-SRC/domain"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCRC16CCITT(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want uint16
+	}{
+		{name: "check value CRC-16/CCITT-FALSE", data: "123456789", want: 0x29B1},
+		{name: "data kosong", data: "", want: 0xFFFF},
+		{
+			// Contoh payload dari spesifikasi EMVCo QRCPS (termasuk karakter UTF-8), CRC A13A
+			name: "contoh EMVCo",
+			data: "00020101021229300012D156000000000510A93FO3230Q31280012D15600000001030812345678520441115802CN5914BEST TRANSPORT6007BEIJING64200002ZH0104最佳运输0202北京540523.7253031565502016233030412340603***0708A60086670902ME91320016A0112233449988770708123456786304",
+			want: 0xA13A,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, domain.CRC16CCITT(tt.data))
+		})
+	}
+}
+
+func TestBuildQRISPayload(t *testing.T) {
+	merchant := domain.QRISMerchant{
+		Nama:       "TOKO MAKMUR JAYA",
+		Kota:       "JAKARTA",
+		KodePos:    "12345",
+		MCC:        "5411",
+		NMID:       "ID1020012345678",
+		Kriteria:   domain.QRISKriteriaMikro,
+		GUI:        "ID.CO.BANKXYZ.WWW",
+		MerchantID: "123456789",
+		PAN:        "9360001234567890123",
+	}
+	minimal := domain.QRISMerchant{
+		Nama:     merchant.Nama,
+		Kota:     merchant.Kota,
+		MCC:      merchant.MCC,
+		NMID:     merchant.NMID,
+		Kriteria: merchant.Kriteria,
+	}
+
+	tests := []struct {
+		name       string
+		merchant   domain.QRISMerchant
+		jumlah     int
+		billNumber string
+		want       string
+		wantErr    string
+	}{
+		{
+			name:       "lengkap dengan tag 26, kode pos, dan nomor tagihan",
+			merchant:   merchant,
+			jumlah:     15000,
+			billNumber: "PJ-000123",
+			want: "000201010212" +
+				"26640017ID.CO.BANKXYZ.WWW0119936000123456789012302091234567890303UMI" +
+				"51440014ID.CO.QRIS.WWW0215ID10200123456780303UMI" +
+				"52045411" + "5303360" + "540515000" + "5802ID" + "5916TOKO MAKMUR JAYA" + "6007JAKARTA" +
+				"610512345" + "62130109PJ-000123" + "630419FC",
+		},
+		{
+			name:     "tanpa data opsional",
+			merchant: minimal,
+			jumlah:   15000,
+			want: "000201010212" +
+				"51440014ID.CO.QRIS.WWW0215ID10200123456780303UMI" +
+				"52045411" + "5303360" + "540515000" + "5802ID" + "5916TOKO MAKMUR JAYA" + "6007JAKARTA" +
+				"6304CC2B",
+		},
+		{
+			name:     "nominal nol",
+			merchant: merchant,
+			jumlah:   0,
+			wantErr:  "nominal QRIS",
+		},
+		{
+			name:     "nilai tag lebih dari 99 karakter",
+			merchant: domain.QRISMerchant{Nama: "TOKO", Kota: "JAKARTA", MCC: "5411", NMID: "ID1020012345678", Kriteria: "UMI", GUI: strings.Repeat("X", 100)},
+			jumlah:   15000,
+			wantErr:  "tag 00 QRIS terlalu panjang",
+		},
+		{
+			// Nilai tag 26 gabungan sub tag melebihi 99 karakter walaupun setiap sub tag valid
+			name:     "nilai tag gabungan lebih dari 99 karakter",
+			merchant: domain.QRISMerchant{Nama: "TOKO", Kota: "JAKARTA", MCC: "5411", NMID: "ID1020012345678", Kriteria: "UMI", GUI: strings.Repeat("X", 60), PAN: strings.Repeat("9", 30)},
+			jumlah:   15000,
+			wantErr:  "tag 26 QRIS terlalu panjang",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := domain.BuildQRISPayload(tt.merchant, tt.jumlah, tt.billNumber)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestQRISMerchantValidate(t *testing.T) {
+	valid := domain.QRISMerchant{Nama: "TOKO", Kota: "JAKARTA", MCC: "5411", NMID: "ID1020012345678", Kriteria: "UMI"}
+
+	tests := []struct {
+		name    string
+		modify  func(m *domain.QRISMerchant)
+		wantErr bool
+	}{
+		{name: "valid", modify: func(m *domain.QRISMerchant) {}},
+		{name: "kriteria UKE", modify: func(m *domain.QRISMerchant) { m.Kriteria = domain.QRISKriteriaKecil }},
+		{name: "kriteria UME", modify: func(m *domain.QRISMerchant) { m.Kriteria = domain.QRISKriteriaMenengah }},
+		{name: "kriteria UBE", modify: func(m *domain.QRISMerchant) { m.Kriteria = domain.QRISKriteriaBesar }},
+		{name: "kriteria kosong", modify: func(m *domain.QRISMerchant) { m.Kriteria = "" }, wantErr: true},
+		{name: "kriteria tidak dikenal", modify: func(m *domain.QRISMerchant) { m.Kriteria = "UMKM" }, wantErr: true},
+		{name: "NMID kosong", modify: func(m *domain.QRISMerchant) { m.NMID = "" }, wantErr: true},
+		{name: "MCC bukan angka", modify: func(m *domain.QRISMerchant) { m.MCC = "54A1" }, wantErr: true},
+		{name: "MCC 3 digit", modify: func(m *domain.QRISMerchant) { m.MCC = "541" }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := valid
+			tt.modify(&m)
+			err := m.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
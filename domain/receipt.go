@@ -0,0 +1,36 @@
+package domain
+
+import "context"
+
+// Format struk penjualan
+const (
+	ReceiptFormatPDF    = "pdf"
+	ReceiptFormatESCPOS = "escpos"
+	ReceiptFormatText   = "text"
+)
+
+// Lebar kertas printer thermal yang didukung (mm)
+const (
+	ReceiptWidth58 = 58
+	ReceiptWidth80 = 80
+)
+
+// StoreInfo adalah identitas toko yang dicetak di header dan footer struk
+type StoreInfo struct {
+	Nama    string
+	Alamat  string
+	Telepon string
+	NPWP    string
+	Footer  string
+}
+
+// Receipt adalah struk yang sudah dirender beserta content type-nya
+type Receipt struct {
+	ContentType string
+	Filename    string
+	Data        []byte
+}
+
+type ReceiptUseCase interface {
+	Render(ctx context.Context, idPenjualan, format string, lebar int) (Receipt, error)
+}
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Pengelompokan laporan penjualan
+const (
+	ReportGroupProduk   = "produk"
+	ReportGroupInduk    = "induk" // varian digabung ke produk induknya
+	ReportGroupKategori = "kategori"
+	ReportGroupPeriode  = "periode"
+)
+
+// Interval untuk laporan yang dikelompokkan per periode
+const (
+	ReportIntervalDay   = "day"
+	ReportIntervalMonth = "month"
+)
+
+// ReportFilter membatasi data laporan; From/To kosong berarti tanpa batas
+type ReportFilter struct {
+	From     time.Time
+	To       time.Time
+	GroupBy  string
+	Interval string
+}
+
+// MarginReportRow adalah margin kotor satu kelompok (produk, kategori, atau periode)
+type MarginReportRow struct {
+	Key          string  `json:"key" bson:"_id"`
+	Nama         string  `json:"nama,omitempty" bson:"nama"`
+	Jumlah       int     `json:"jumlah" bson:"jumlah"`       // dalam satuan dasar
+	Penjualan    int64   `json:"penjualan" bson:"penjualan"` // DPP baris (tanpa PPN) setelah diskon baris dan diskon transaksi
+	HPP          int64   `json:"hpp" bson:"hpp"`
+	Margin       int64   `json:"margin" bson:"-"`
+	MarginPersen float64 `json:"margin_persen" bson:"-"`
+	// JumlahTanpaHPP adalah unit terjual yang belum memiliki snapshot HPP (data lama)
+	JumlahTanpaHPP int `json:"jumlah_tanpa_hpp" bson:"jumlah_tanpa_hpp"`
+}
+
+type MarginReport struct {
+	GroupBy  string            `json:"group_by"`
+	Interval string            `json:"interval,omitempty"`
+	Rows     []MarginReportRow `json:"rows"`
+	Total    MarginReportRow   `json:"total"`
+}
+
+// TaxReportRow adalah DPP dan PPN untuk satu periode dan tarif
+type TaxReportRow struct {
+	Periode         string  `json:"periode" bson:"periode"`
+	Tarif           float64 `json:"tarif" bson:"tarif"`
+	JumlahTransaksi int     `json:"jumlah_transaksi" bson:"jumlah_transaksi"`
+	DPP             int64   `json:"dpp" bson:"dpp"`
+	PPN             int64   `json:"ppn" bson:"ppn"`
+}
+
+// TaxReport adalah rekap PPN per periode untuk pelaporan pajak
+type TaxReport struct {
+	Interval string         `json:"interval"`
+	Rows     []TaxReportRow `json:"rows"`
+	TotalDPP int64          `json:"total_dpp"`
+	TotalPPN int64          `json:"total_ppn"`
+}
+
+// PaymentReportRow adalah total pembayaran satu metode (per periode jika interval diisi)
+type PaymentReportRow struct {
+	Periode         string `json:"periode,omitempty" bson:"periode"`
+	Metode          string `json:"metode" bson:"metode"`
+	JumlahTransaksi int    `json:"jumlah_transaksi" bson:"jumlah_transaksi"`
+	Total           int64  `json:"total" bson:"total"`
+}
+
+// PaymentReport adalah rincian penjualan per metode pembayaran
+type PaymentReport struct {
+	Interval string             `json:"interval,omitempty"`
+	Rows     []PaymentReportRow `json:"rows"`
+	Total    int64              `json:"total"`
+}
+
+type ReportRepository interface {
+	GetMarginReport(ctx context.Context, filter ReportFilter) ([]MarginReportRow, error)
+	GetTaxReport(ctx context.Context, filter ReportFilter) ([]TaxReportRow, error)
+	GetPaymentReport(ctx context.Context, filter ReportFilter) ([]PaymentReportRow, error)
+}
+
+type ReportUseCase interface {
+	GetMarginReport(ctx context.Context, filter ReportFilter) (MarginReport, error)
+	GetTaxReport(ctx context.Context, filter ReportFilter) (TaxReport, error)
+	GetPaymentReport(ctx context.Context, filter ReportFilter) (PaymentReport, error)
+}
@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultSatuanDasar dipakai untuk produk yang belum menentukan satuan dasarnya
+const DefaultSatuanDasar = "pcs"
+
+// ProdukSatuan adalah satuan tambahan produk, misalnya 1 dus = 24 pcs.
+// Konversi adalah jumlah satuan dasar dalam satu satuan ini.
+type ProdukSatuan struct {
+	Nama     string `json:"nama" bson:"nama"`
+	Konversi int    `json:"konversi" bson:"konversi"`
+	// Harga jual per satuan ini; 0 berarti Harga produk x Konversi
+	Harga   int    `json:"harga" bson:"harga"`
+	Barcode string `json:"barcode,omitempty" bson:"barcode,omitempty"`
+}
+
+// NamaSatuanDasar mengembalikan satuan dasar produk, tempat stok selalu dicatat
+func (p *Produk) NamaSatuanDasar() string {
+	if p.SatuanDasar == "" {
+		return DefaultSatuanDasar
+	}
+	return p.SatuanDasar
+}
+
+// ResolveSatuan mencari satuan berdasarkan nama (tanpa membedakan huruf besar/kecil).
+// Nama kosong berarti satuan dasar. Harga yang dikembalikan sudah terisi.
+func (p *Produk) ResolveSatuan(nama string) (ProdukSatuan, error) {
+	nama = strings.TrimSpace(nama)
+	if nama == "" || strings.EqualFold(nama, p.NamaSatuanDasar()) {
+		return ProdukSatuan{Nama: p.NamaSatuanDasar(), Konversi: 1, Harga: p.Harga}, nil
+	}
+
+	for _, satuan := range p.Satuan {
+		if strings.EqualFold(satuan.Nama, nama) {
+			if satuan.Harga <= 0 {
+				satuan.Harga = p.Harga * satuan.Konversi
+			}
+			return satuan, nil
+		}
+	}
+
+	return ProdukSatuan{}, fmt.Errorf("satuan %s tidak tersedia untuk produk %s", nama, p.NamaProduk)
+}
+
+// ValidateSatuan memastikan daftar satuan produk konsisten
+func (p *Produk) ValidateSatuan() error {
+	seen := map[string]bool{strings.ToLower(p.NamaSatuanDasar()): true}
+	for i, satuan := range p.Satuan {
+		nama := strings.ToLower(strings.TrimSpace(satuan.Nama))
+		if nama == "" {
+			return fmt.Errorf("nama satuan ke-%d tidak boleh kosong", i+1)
+		}
+		if seen[nama] {
+			return fmt.Errorf("satuan %s didefinisikan lebih dari sekali", satuan.Nama)
+		}
+		seen[nama] = true
+
+		if satuan.Konversi <= 1 {
+			return fmt.Errorf("konversi satuan %s harus lebih dari 1 %s", satuan.Nama, p.NamaSatuanDasar())
+		}
+		if satuan.Harga < 0 {
+			return fmt.Errorf("harga satuan %s tidak boleh negatif", satuan.Nama)
+		}
+	}
+	return nil
+}
+
+// JumlahDasar mengembalikan jumlah baris penjualan dalam satuan dasar.
+// Data lama tanpa konversi dianggap sudah dalam satuan dasar.
+func (p ProdukJual) JumlahDasar() int {
+	if p.Konversi <= 0 {
+		return p.JumlahProduk
+	}
+	return p.JumlahProduk * p.Konversi
+}
@@ -0,0 +1,74 @@
+package domain_test
+
+import (
+	"SIE-SRC/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveSatuan(t *testing.T) {
+	produk := domain.Produk{
+		NamaProduk: "Air Mineral",
+		Harga:      3000,
+		Satuan: []domain.ProdukSatuan{
+			{Nama: "Dus", Konversi: 24, Harga: 65000},
+			{Nama: "Pak", Konversi: 6},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		satuan  string
+		want    domain.ProdukSatuan
+		wantErr bool
+	}{
+		{name: "kosong berarti satuan dasar", satuan: "", want: domain.ProdukSatuan{Nama: "pcs", Konversi: 1, Harga: 3000}},
+		{name: "satuan dasar tanpa membedakan huruf", satuan: " PCS ", want: domain.ProdukSatuan{Nama: "pcs", Konversi: 1, Harga: 3000}},
+		{name: "harga satuan sendiri", satuan: "dus", want: domain.ProdukSatuan{Nama: "Dus", Konversi: 24, Harga: 65000}},
+		{name: "harga kosong dari harga dasar x konversi", satuan: "Pak", want: domain.ProdukSatuan{Nama: "Pak", Konversi: 6, Harga: 18000}},
+		{name: "satuan tidak tersedia", satuan: "karton", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := produk.ResolveSatuan(tt.satuan)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestResolveSatuanCustomSatuanDasar(t *testing.T) {
+	produk := domain.Produk{Harga: 12000, SatuanDasar: "kg"}
+
+	got, err := produk.ResolveSatuan("KG")
+	assert.NoError(t, err)
+	assert.Equal(t, domain.ProdukSatuan{Nama: "kg", Konversi: 1, Harga: 12000}, got)
+
+	_, err = produk.ResolveSatuan("pcs")
+	assert.Error(t, err)
+}
+
+func TestJumlahDasar(t *testing.T) {
+	tests := []struct {
+		name string
+		line domain.ProdukJual
+		want int
+	}{
+		{name: "satuan dasar", line: domain.ProdukJual{JumlahProduk: 5, Konversi: 1}, want: 5},
+		{name: "satuan besar dikali konversi", line: domain.ProdukJual{JumlahProduk: 2, Konversi: 24}, want: 48},
+		{name: "data lama tanpa konversi", line: domain.ProdukJual{JumlahProduk: 3}, want: 3},
+		{name: "konversi negatif dianggap satuan dasar", line: domain.ProdukJual{JumlahProduk: 3, Konversi: -1}, want: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.line.JumlahDasar())
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Alasan perubahan stok yang dicatat di kartu stok
+const (
+	StockReasonSale             = "sale"
+	StockReasonSaleEdit         = "sale_edit"
+	StockReasonSaleDelete       = "sale_delete"
+	StockReasonImport           = "import"
+	StockReasonManualAdjustment = "manual_adjustment"
+	StockReasonOpname           = "opname"
+	StockReasonPurchase         = "purchase"
+)
+
+// StockRef menjelaskan asal sebuah perubahan stok
+type StockRef struct {
+	Reason string
+	RefID  string
+	User   string
+}
+
+// StockMovement adalah satu baris kartu stok
+type StockMovement struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	IDProduk  string             `json:"id_produk" bson:"id_produk"`
+	Delta     int                `json:"delta" bson:"delta"`
+	Saldo     int                `json:"saldo" bson:"saldo"`
+	Reason    string             `json:"reason" bson:"reason"`
+	RefID     string             `json:"ref_id" bson:"ref_id"`
+	User      string             `json:"user" bson:"user"`
+	Timestamp time.Time          `json:"timestamp" bson:"timestamp"`
+}
+
+type StockMovementRepository interface {
+	Record(ctx context.Context, movements ...StockMovement) error
+	GetByProduk(ctx context.Context, idProduk string, from, to time.Time) ([]StockMovement, error)
+}
@@ -0,0 +1,14 @@
+package domain
+
+import (
+	"context"
+	"io"
+)
+
+// FileStorage menyimpan file unggahan (misalnya gambar produk).
+// key adalah path relatif seperti "produk/001/abc.jpg"; URL mengembalikan alamat publiknya.
+type FileStorage interface {
+	Save(ctx context.Context, key string, r io.Reader) error
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
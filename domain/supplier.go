@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type Supplier struct {
+	IDSupplier   string `json:"id_supplier" bson:"_id"`
+	NamaSupplier string `json:"nama_supplier" bson:"nama_supplier"`
+	NamaKontak   string `json:"nama_kontak" bson:"nama_kontak"`
+	Telepon      string `json:"telepon" bson:"telepon"`
+	Email        string `json:"email" bson:"email"`
+	Alamat       string `json:"alamat" bson:"alamat"`
+	NPWP         string `json:"npwp" bson:"npwp"`
+	// TerminPembayaran adalah jatuh tempo pembayaran dalam hari (0 = tunai)
+	TerminPembayaran int        `json:"termin_pembayaran" bson:"termin_pembayaran"`
+	Catatan          string     `json:"catatan" bson:"catatan"`
+	UpdatedAt        time.Time  `json:"updated_at" bson:"updated_at"`
+	IsDeleted        *time.Time `json:"is_deleted" bson:"is_deleted"`
+}
+
+// Validate memeriksa data master supplier sebelum disimpan
+func (s *Supplier) Validate() error {
+	if s.NamaSupplier == "" {
+		return fmt.Errorf("nama supplier tidak boleh kosong")
+	}
+	if s.TerminPembayaran < 0 {
+		return fmt.Errorf("termin pembayaran tidak boleh negatif")
+	}
+	return nil
+}
+
+// SupplierPrice adalah harga beli sebuah produk dari supplier tertentu
+type SupplierPrice struct {
+	IDSupplier string    `json:"id_supplier" bson:"id_supplier"`
+	IDProduk   string    `json:"id_produk" bson:"id_produk"`
+	KodeProduk string    `json:"barcode_produk" bson:"barcode_produk"`
+	NamaProduk string    `json:"nama_produk" bson:"nama_produk"`
+	HargaBeli  int       `json:"harga_beli" bson:"harga_beli"`
+	MinOrder   int       `json:"min_order" bson:"min_order"`
+	UpdatedAt  time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// SupplierPriceImportResult merangkum hasil import daftar harga supplier
+type SupplierPriceImportResult struct {
+	Imported int      `json:"imported"`
+	Skipped  []string `json:"skipped"`
+}
+
+type SupplierRepository interface {
+	Create(ctx context.Context, bd *Supplier) (Supplier, error)
+	GetAll(ctx context.Context) ([]Supplier, error)
+	GetByID(ctx context.Context, id string) (*Supplier, error)
+	Update(ctx context.Context, bd *Supplier) error
+	Delete(ctx context.Context, id string) error
+	GetPriceList(ctx context.Context, id string) ([]SupplierPrice, error)
+	ImportPriceList(ctx context.Context, id string, prices []SupplierPrice) (SupplierPriceImportResult, error)
+}
+
+type SupplierUseCase interface {
+	Create(ctx context.Context, bd *Supplier) (Supplier, error)
+	GetAll(ctx context.Context) ([]Supplier, error)
+	GetByID(ctx context.Context, id string) (*Supplier, error)
+	Update(ctx context.Context, bd *Supplier) error
+	Delete(ctx context.Context, id string) error
+	GetPriceList(ctx context.Context, id string) ([]SupplierPrice, error)
+	ImportPriceList(ctx context.Context, id string, prices []SupplierPrice) (SupplierPriceImportResult, error)
+}
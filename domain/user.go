@@ -31,3 +31,16 @@ type UserUseCase interface {
 	AuthenticateUser(ctx context.Context, username, password string) (*User, error)
 	GetAll(ctx context.Context) ([]User, error)
 }
+
+type userContextKey struct{}
+
+// ContextWithUser menyimpan username yang sedang login ke dalam context
+func ContextWithUser(ctx context.Context, username string) context.Context {
+	return context.WithValue(ctx, userContextKey{}, username)
+}
+
+// UserFromContext mengambil username dari context, kosong jika tidak ada
+func UserFromContext(ctx context.Context) string {
+	username, _ := ctx.Value(userContextKey{}).(string)
+	return username
+}
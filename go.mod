@@ -4,13 +4,16 @@ go 1.23.1
 
 require (
 	github.com/asaskevich/govalidator v0.0.0-20230301143203-a9d515a09cc2
+	github.com/boombuler/barcode v1.1.0
 	github.com/dgrijalva/jwt-go v3.2.0+incompatible
+	github.com/go-pdf/fpdf v0.9.0
 	github.com/gofiber/fiber/v2 v2.52.5
 	github.com/joho/godotenv v1.5.1
 	github.com/pandeptwidyaop/golog v0.0.5
 	github.com/stretchr/testify v1.9.0
 	github.com/xuri/excelize/v2 v2.9.0
 	go.mongodb.org/mongo-driver v1.17.1
+	golang.org/x/image v0.18.0
 )
 
 require (
@@ -55,3 +55,40 @@ func AuthMiddleware(roles ...string) fiber.Handler {
 		})
 	}
 }
+
+// OptionalAuthMiddleware membaca token jika dikirim tanpa menolak request anonim.
+// Dipakai pada route yang perlu mencatat user untuk audit.
+func OptionalAuthMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		tokenString := strings.TrimPrefix(c.Get("Authorization"), "Bearer ")
+		if tokenString == "" {
+			return c.Next()
+		}
+
+		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+			}
+			return []byte(os.Getenv("JWT_SECRET_KEY")), nil
+		})
+		if err == nil && token.Valid {
+			c.Locals("user", token)
+		}
+
+		return c.Next()
+	}
+}
+
+// CurrentUsername mengambil username dari token yang disimpan middleware, kosong jika anonim
+func CurrentUsername(c *fiber.Ctx) string {
+	token, ok := c.Locals("user").(*jwt.Token)
+	if !ok {
+		return ""
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return ""
+	}
+	username, _ := claims["username"].(string)
+	return username
+}
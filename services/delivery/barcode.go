@@ -0,0 +1,111 @@
+package delivery
+
+import (
+	"SIE-SRC/domain"
+	"SIE-SRC/middleware"
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type HttpDeliveryBarcode struct {
+	HTTP domain.BarcodeUseCase
+}
+
+func NewHttpDeliveryBarcode(app fiber.Router, HTTP domain.BarcodeUseCase) {
+	handler := HttpDeliveryBarcode{
+		HTTP: HTTP,
+	}
+
+	group := app.Group("/barcode")
+	group.Get("/image/:id_produk", handler.GetImage)
+	group.Post("/label", handler.GenerateLabels)
+
+	manage := app.Group("/barcode")
+	manage.Use(middleware.AuthMiddleware("admin", "owner"))
+	manage.Post("/generate", handler.GenerateMissing)
+}
+
+// queryDimension membaca ukuran pixel dari query, 0 jika tidak dikirim
+func queryDimension(c *fiber.Ctx, key string) (int, bool) {
+	value := c.Query(key)
+	if value == "" {
+		return 0, true
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed <= 0 {
+		return 0, false
+	}
+	return parsed, true
+}
+
+// GetImage mengembalikan PNG barcode produk (?format=code128|ean13&width=&height=)
+func (d *HttpDeliveryBarcode) GetImage(c *fiber.Ctx) error {
+	width, ok := queryDimension(c, "width")
+	if !ok {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "Parameter width harus berupa angka positif",
+		})
+	}
+	height, ok := queryDimension(c, "height")
+	if !ok {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "Parameter height harus berupa angka positif",
+		})
+	}
+
+	image, err := d.HTTP.GetImage(context.Background(), c.Params("id_produk"), domain.BarcodeImage{
+		Format: c.Query("format"),
+		Width:  width,
+		Height: height,
+	})
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	c.Set(fiber.HeaderContentType, "image/png")
+	return c.Status(http.StatusOK).Send(image)
+}
+
+// GenerateLabels menerima {"items": [{"id_produk": "...", "jumlah": 3}]} dan mengembalikan PDF
+func (d *HttpDeliveryBarcode) GenerateLabels(c *fiber.Ctx) error {
+	var body struct {
+		Items []domain.LabelItem `json:"items"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "Format data tidak valid",
+		})
+	}
+
+	pdf, err := d.HTTP.GenerateLabels(context.Background(), body.Items)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	c.Set(fiber.HeaderContentType, "application/pdf")
+	c.Set(fiber.HeaderContentDisposition, `inline; filename="label-barcode.pdf"`)
+	return c.Status(http.StatusOK).Send(pdf)
+}
+
+// GenerateMissing memberi EAN-13 internal ke produk yang belum punya barcode
+func (d *HttpDeliveryBarcode) GenerateMissing(c *fiber.Ctx) error {
+	data, err := d.HTTP.GenerateMissing(userContext(c))
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+			"data":  data,
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "Barcode berhasil dibuat untuk " + strconv.Itoa(len(data)) + " produk",
+		"data":    data,
+	})
+}
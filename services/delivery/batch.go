@@ -0,0 +1,93 @@
+package delivery
+
+import (
+	"SIE-SRC/domain"
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type HttpDeliveryStockBatch struct {
+	HTTP domain.StockBatchUseCase
+}
+
+func NewHttpDeliveryStockBatch(app fiber.Router, HTTP domain.StockBatchUseCase) {
+	handler := HttpDeliveryStockBatch{
+		HTTP: HTTP,
+	}
+
+	group := app.Group("/batch")
+	group.Post("/create", handler.Create)
+	group.Get("/by-produk/:id_produk", handler.GetByProduk)
+	group.Get("/expiring", handler.GetExpiring)
+}
+
+// Create mendaftarkan tanggal kadaluarsa untuk stok yang sudah ada (tanpa mengubah stok)
+func (d *HttpDeliveryStockBatch) Create(c *fiber.Ctx) error {
+	var batch domain.StockBatch
+	if err := c.BodyParser(&batch); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "Format data tidak valid",
+		})
+	}
+
+	created, err := d.HTTP.Create(context.Background(), &batch)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(http.StatusCreated).JSON(fiber.Map{
+		"message": "Batch berhasil dicatat",
+		"data":    created,
+	})
+}
+
+func (d *HttpDeliveryStockBatch) GetByProduk(c *fiber.Ctx) error {
+	id := c.Params("id_produk")
+	if id == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID produk diperlukan",
+		})
+	}
+
+	data, err := d.HTTP.GetByProduk(context.Background(), id)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal untuk mendapatkan Data",
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"data": data,
+	})
+}
+
+// GetExpiring menampilkan batch yang kadaluarsa dalam N hari (default 30)
+func (d *HttpDeliveryStockBatch) GetExpiring(c *fiber.Ctx) error {
+	days := 30
+	if value := c.Query("days"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+				"error": "Parameter days harus berupa angka positif",
+			})
+		}
+		days = parsed
+	}
+
+	data, err := d.HTTP.GetExpiring(context.Background(), days)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal untuk mendapatkan Data",
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"days": days,
+		"data": data,
+	})
+}
@@ -0,0 +1,35 @@
+package delivery
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// setETag menulis versi dokumen ke header ETag
+func setETag(c *fiber.Ctx, version int64) {
+	c.Set(fiber.HeaderETag, fmt.Sprintf(`"%d"`, version))
+}
+
+// parseIfMatch membaca versi dari header If-Match. ok bernilai false jika header tidak
+// dikirim atau berisi "*". Versi 0 berarti dokumen lama yang belum memiliki field version.
+func parseIfMatch(c *fiber.Ctx) (version int64, ok bool, err error) {
+	value := strings.TrimSpace(c.Get(fiber.HeaderIfMatch))
+	if value == "" || value == "*" {
+		return 0, false, nil
+	}
+
+	value = strings.TrimPrefix(value, "W/")
+	value = strings.Trim(value, `"`)
+
+	version, err = strconv.ParseInt(value, 10, 64)
+	if err != nil || version < 0 {
+		return 0, false, fmt.Errorf("header If-Match tidak valid")
+	}
+	return version, true, nil
+}
+
+// errVersionRequired adalah pesan 428 untuk update tanpa If-Match maupun field version
+const errVersionRequired = "header If-Match atau field version wajib dikirim untuk mencegah perubahan yang saling menimpa"
@@ -0,0 +1,96 @@
+package delivery
+
+import (
+	"SIE-SRC/domain"
+	"SIE-SRC/middleware"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type HttpDeliveryProdukGambar struct {
+	HTTP domain.ProdukGambarUseCase
+}
+
+func NewHttpDeliveryProdukGambar(app fiber.Router, HTTP domain.ProdukGambarUseCase) {
+	handler := HttpDeliveryProdukGambar{
+		HTTP: HTTP,
+	}
+
+	manage := app.Group("/produk/gambar")
+	manage.Use(middleware.AuthMiddleware("admin", "owner"))
+	manage.Post("/:id_produk", handler.Upload)
+	manage.Put("/:id_produk/urutan", handler.Reorder)
+	manage.Delete("/:id_produk/:id_gambar", handler.Delete)
+}
+
+// Upload menerima gambar dari form multipart dengan field "file"
+func (d *HttpDeliveryProdukGambar) Upload(c *fiber.Ctx) error {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "File gambar tidak ditemukan",
+		})
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "Gagal membuka file",
+		})
+	}
+	defer file.Close()
+
+	gambar, err := d.HTTP.Upload(userContext(c), domain.ProdukGambarUpload{
+		IDProduk: c.Params("id_produk"),
+		Size:     fileHeader.Size,
+		File:     file,
+	})
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(http.StatusCreated).JSON(fiber.Map{
+		"message": "Gambar produk berhasil diunggah",
+		"data":    gambar,
+	})
+}
+
+// Reorder menerima {"ids": [...]} berisi semua id_gambar dalam urutan baru
+func (d *HttpDeliveryProdukGambar) Reorder(c *fiber.Ctx) error {
+	var body struct {
+		IDs []string `json:"ids"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "Format data tidak valid",
+		})
+	}
+
+	gambar, err := d.HTTP.Reorder(userContext(c), c.Params("id_produk"), body.IDs)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "Urutan gambar berhasil diperbarui",
+		"data":    gambar,
+	})
+}
+
+func (d *HttpDeliveryProdukGambar) Delete(c *fiber.Ctx) error {
+	err := d.HTTP.Delete(userContext(c), c.Params("id_produk"), c.Params("id_gambar"))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "Gambar produk berhasil dihapus",
+	})
+}
@@ -0,0 +1,101 @@
+package delivery
+
+import (
+	"SIE-SRC/domain"
+	"SIE-SRC/middleware"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/xuri/excelize/v2"
+)
+
+// userContext membuat context yang membawa username dari token (jika ada)
+func userContext(c *fiber.Ctx) context.Context {
+	return domain.ContextWithUser(context.Background(), middleware.CurrentUsername(c))
+}
+
+// parseDateRange membaca query from dan to (format YYYY-MM-DD).
+// Tanggal to bersifat inklusif sehingga dikembalikan sebagai awal hari berikutnya.
+func parseDateRange(c *fiber.Ctx) (time.Time, time.Time, error) {
+	var from, to time.Time
+	var err error
+
+	if value := c.Query("from"); value != "" {
+		from, err = time.ParseInLocation("2006-01-02", value, time.Local)
+		if err != nil {
+			return from, to, fmt.Errorf("format tanggal from tidak valid, gunakan YYYY-MM-DD")
+		}
+	}
+
+	if value := c.Query("to"); value != "" {
+		to, err = time.ParseInLocation("2006-01-02", value, time.Local)
+		if err != nil {
+			return from, to, fmt.Errorf("format tanggal to tidak valid, gunakan YYYY-MM-DD")
+		}
+		to = to.AddDate(0, 0, 1)
+	}
+
+	return from, to, nil
+}
+
+// isSpreadsheetFile mengecek apakah file berupa CSV atau XLSX
+func isSpreadsheetFile(filename string) bool {
+	filename = strings.ToLower(filename)
+	return strings.HasSuffix(filename, ".csv") || strings.HasSuffix(filename, ".xlsx")
+}
+
+// readSpreadsheetRows membaca isi file CSV atau sheet pertama XLSX tanpa baris header
+func readSpreadsheetRows(file io.Reader, filename string) ([][]string, error) {
+	var rows [][]string
+
+	switch filename = strings.ToLower(filename); {
+	case strings.HasSuffix(filename, ".csv"):
+		reader := csv.NewReader(file)
+		reader.FieldsPerRecord = -1 // Izinkan jumlah kolom fleksibel
+
+		records, err := reader.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("Gagal membaca file CSV")
+		}
+		rows = records
+
+	case strings.HasSuffix(filename, ".xlsx"):
+		xlsx, err := excelize.OpenReader(file)
+		if err != nil {
+			return nil, fmt.Errorf("Gagal membaca file Excel")
+		}
+		defer xlsx.Close()
+
+		// Ambil sheet pertama
+		rows, err = xlsx.GetRows(xlsx.GetSheetName(0))
+		if err != nil {
+			return nil, fmt.Errorf("Gagal membaca sheet Excel")
+		}
+
+	default:
+		return nil, fmt.Errorf("Format file tidak didukung. Gunakan CSV atau XLSX")
+	}
+
+	// Skip header row
+	if len(rows) > 1 {
+		rows = rows[1:]
+	}
+
+	return rows, nil
+}
+
+// parseHargaCell mengubah isi sel harga (boleh memakai pemisah ribuan koma) menjadi int
+func parseHargaCell(value string) (int, error) {
+	hargaStr := strings.TrimSpace(strings.ReplaceAll(value, ",", ""))
+	hargaFloat, err := strconv.ParseFloat(hargaStr, 64)
+	if err != nil {
+		return 0, err
+	}
+	return int(hargaFloat), nil
+}
@@ -0,0 +1,128 @@
+package delivery
+
+import (
+	"SIE-SRC/domain"
+	"SIE-SRC/middleware"
+	"context"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type HttpDeliveryProdukInduk struct {
+	HTTP domain.ProdukIndukUseCase
+}
+
+func NewHttpDeliveryProdukInduk(app fiber.Router, HTTP domain.ProdukIndukUseCase) {
+	handler := HttpDeliveryProdukInduk{
+		HTTP: HTTP,
+	}
+
+	group := app.Group("/induk")
+	group.Get("/getall", handler.GetAll)
+	group.Get("/by-id/:id_induk", handler.GetByID)
+	group.Get("/grouped", handler.GetGrouped)
+
+	manage := app.Group("/induk")
+	manage.Use(middleware.AuthMiddleware("admin", "owner"))
+	manage.Post("/create", handler.Create)
+	manage.Put("/update/:id_induk", handler.Update)
+	manage.Delete("/delete/:id_induk", handler.Delete)
+}
+
+func (d *HttpDeliveryProdukInduk) Create(c *fiber.Ctx) error {
+	var induk domain.ProdukInduk
+	if err := c.BodyParser(&induk); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "Format data tidak valid",
+		})
+	}
+
+	created, err := d.HTTP.Create(context.Background(), &induk)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(http.StatusCreated).JSON(fiber.Map{
+		"message": "Produk induk berhasil dibuat",
+		"data":    created,
+	})
+}
+
+func (d *HttpDeliveryProdukInduk) GetAll(c *fiber.Ctx) error {
+	data, err := d.HTTP.GetAll(context.Background())
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal untuk mendapatkan Data",
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"data": data,
+	})
+}
+
+// GetByID menampilkan produk induk beserta variannya
+func (d *HttpDeliveryProdukInduk) GetByID(c *fiber.Ctx) error {
+	data, err := d.HTTP.GetByID(context.Background(), c.Params("id_induk"))
+	if err != nil {
+		return c.Status(http.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"data": data,
+	})
+}
+
+// GetGrouped menampilkan daftar produk yang dikelompokkan per induk (?supplier= opsional)
+func (d *HttpDeliveryProdukInduk) GetGrouped(c *fiber.Ctx) error {
+	data, err := d.HTTP.GetGrouped(context.Background(), domain.ProdukFilter{
+		IDSupplier: c.Query("supplier"),
+	})
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal untuk mendapatkan Data",
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"data": data,
+	})
+}
+
+func (d *HttpDeliveryProdukInduk) Update(c *fiber.Ctx) error {
+	var induk domain.ProdukInduk
+	if err := c.BodyParser(&induk); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "Format data tidak valid",
+		})
+	}
+	induk.IDInduk = c.Params("id_induk")
+
+	if err := d.HTTP.Update(context.Background(), &induk); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "Produk induk berhasil diperbarui",
+		"data":    induk,
+	})
+}
+
+func (d *HttpDeliveryProdukInduk) Delete(c *fiber.Ctx) error {
+	if err := d.HTTP.Delete(context.Background(), c.Params("id_induk")); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "Produk induk berhasil dihapus",
+	})
+}
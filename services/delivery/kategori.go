@@ -0,0 +1,164 @@
+package delivery
+
+import (
+	"SIE-SRC/domain"
+	"SIE-SRC/middleware"
+	"context"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type HttpDeliveryKategori struct {
+	HTTP domain.KategoriUseCase
+}
+
+func NewHttpDeliveryKategori(app fiber.Router, HTTP domain.KategoriUseCase) {
+	handler := HttpDeliveryKategori{
+		HTTP: HTTP,
+	}
+
+	group := app.Group("/kategori")
+	group.Get("/getall", handler.GetTree)
+	group.Get("/by-id/:id_kategori", handler.GetByID)
+
+	// Perubahan pohon kategori hanya untuk admin dan owner
+	manage := app.Group("/kategori")
+	manage.Use(middleware.AuthMiddleware("admin", "owner"))
+	manage.Post("/create", handler.Create)
+	manage.Put("/rename/:id_kategori", handler.Rename)
+	manage.Put("/pajak/:id_kategori", handler.SetTarifPajak)
+	manage.Delete("/delete/:id_kategori", handler.Delete)
+	manage.Post("/merge", handler.Merge)
+}
+
+func (d *HttpDeliveryKategori) Create(c *fiber.Ctx) error {
+	var kategori domain.Kategori
+	if err := c.BodyParser(&kategori); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "Format data tidak valid",
+		})
+	}
+
+	created, err := d.HTTP.Create(context.Background(), &kategori)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(http.StatusCreated).JSON(fiber.Map{
+		"message": "Kategori berhasil dibuat",
+		"data":    created,
+	})
+}
+
+// GetTree menampilkan pohon kategori beserta jumlah produk
+func (d *HttpDeliveryKategori) GetTree(c *fiber.Ctx) error {
+	data, err := d.HTTP.GetTree(context.Background())
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal untuk mendapatkan Data",
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"data": data,
+	})
+}
+
+func (d *HttpDeliveryKategori) GetByID(c *fiber.Ctx) error {
+	data, err := d.HTTP.GetByID(context.Background(), c.Params("id_kategori"))
+	if err != nil {
+		return c.Status(http.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"data": data,
+	})
+}
+
+// Rename mengganti nama kategori beserta field kategori di semua produk
+func (d *HttpDeliveryKategori) Rename(c *fiber.Ctx) error {
+	var body struct {
+		Nama string `json:"nama"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "Format data tidak valid",
+		})
+	}
+
+	modified, err := d.HTTP.Rename(context.Background(), c.Params("id_kategori"), body.Nama)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"message":       "Kategori berhasil diganti nama",
+		"produk_diubah": modified,
+	})
+}
+
+// SetTarifPajak menerima {"tarif_pajak": 11}; null menghapus tarif khusus kategori
+func (d *HttpDeliveryKategori) SetTarifPajak(c *fiber.Ctx) error {
+	var body struct {
+		TarifPajak *float64 `json:"tarif_pajak"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "Format data tidak valid",
+		})
+	}
+
+	if err := d.HTTP.SetTarifPajak(context.Background(), c.Params("id_kategori"), body.TarifPajak); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "Tarif pajak kategori berhasil diperbarui",
+	})
+}
+
+func (d *HttpDeliveryKategori) Delete(c *fiber.Ctx) error {
+	if err := d.HTTP.Delete(context.Background(), c.Params("id_kategori")); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "Kategori berhasil dihapus",
+	})
+}
+
+// Merge menggabungkan kategori sumber ke kategori tujuan
+func (d *HttpDeliveryKategori) Merge(c *fiber.Ctx) error {
+	var body struct {
+		Source string `json:"source"`
+		Target string `json:"target"`
+	}
+	if err := c.BodyParser(&body); err != nil || body.Source == "" || body.Target == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "source dan target wajib diisi",
+		})
+	}
+
+	result, err := d.HTTP.Merge(context.Background(), body.Source, body.Target)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "Kategori berhasil digabung",
+		"data":    result,
+	})
+}
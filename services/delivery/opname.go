@@ -0,0 +1,259 @@
+package delivery
+
+import (
+	"SIE-SRC/domain"
+	"SIE-SRC/middleware"
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/xuri/excelize/v2"
+)
+
+type HttpDeliveryOpname struct {
+	HTTP domain.OpnameUseCase
+}
+
+func NewHttpDeliveryOpname(app fiber.Router, HTTP domain.OpnameUseCase) {
+	handler := HttpDeliveryOpname{
+		HTTP: HTTP,
+	}
+
+	group := app.Group("/opname")
+	group.Use(middleware.OptionalAuthMiddleware())
+	group.Post("/open", handler.Create)
+	group.Get("/getall", handler.GetAll)
+	group.Get("/by-id/:id_opname", handler.GetByID)
+	group.Put("/count/:id_opname", handler.SubmitCounts)
+	group.Post("/upload/:id_opname", handler.UploadCounts)
+	group.Get("/variance/:id_opname", handler.GetVariance)
+
+	// Approval memposting penyesuaian stok, hanya untuk admin dan owner
+	approval := app.Group("/opname/approve")
+	approval.Use(middleware.AuthMiddleware("admin", "owner"))
+	approval.Post("/:id_opname", handler.Approve)
+}
+
+func (d *HttpDeliveryOpname) Create(c *fiber.Ctx) error {
+	var opname domain.Opname
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&opname); err != nil {
+			return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+				"error": "Gagal untuk mem-parsing request body",
+			})
+		}
+	}
+
+	created, err := d.HTTP.Create(userContext(c), &opname)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal membuka sesi opname: " + err.Error(),
+		})
+	}
+
+	return c.Status(http.StatusCreated).JSON(fiber.Map{
+		"message": "Sesi opname berhasil dibuka",
+		"data":    created,
+	})
+}
+
+func (d *HttpDeliveryOpname) GetAll(c *fiber.Ctx) error {
+	val, err := d.HTTP.GetAll(context.Background())
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal untuk mendapatkan Data",
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"data": val,
+	})
+}
+
+func (d *HttpDeliveryOpname) GetByID(c *fiber.Ctx) error {
+	id := c.Params("id_opname")
+	if id == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID opname diperlukan",
+		})
+	}
+
+	data, err := d.HTTP.GetByID(context.Background(), id)
+	if err != nil {
+		return c.Status(http.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "Data ditemukan",
+		"data":    data,
+	})
+}
+
+func (d *HttpDeliveryOpname) SubmitCounts(c *fiber.Ctx) error {
+	id := c.Params("id_opname")
+	if id == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID opname diperlukan",
+		})
+	}
+
+	var items []domain.OpnameItem
+	if err := c.BodyParser(&items); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "Format data tidak valid",
+		})
+	}
+
+	return d.submitCounts(c, id, items)
+}
+
+// UploadCounts menerima hasil hitung dari file XLSX.
+// Kolom: ID produk atau barcode, stok fisik. Baris pertama dianggap header.
+func (d *HttpDeliveryOpname) UploadCounts(c *fiber.Ctx) error {
+	id := c.Params("id_opname")
+	if id == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID opname diperlukan",
+		})
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "File tidak ditemukan",
+		})
+	}
+
+	if !strings.HasSuffix(strings.ToLower(fileHeader.Filename), ".xlsx") {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Format file tidak didukung. Gunakan XLSX",
+		})
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal membuka file",
+		})
+	}
+	defer file.Close()
+
+	xlsx, err := excelize.OpenReader(file)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Gagal membaca file Excel",
+		})
+	}
+
+	rows, err := xlsx.GetRows(xlsx.GetSheetName(0))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal membaca sheet Excel",
+		})
+	}
+
+	// Skip header row
+	if len(rows) > 1 {
+		rows = rows[1:]
+	}
+
+	items := make([]domain.OpnameItem, 0, len(rows))
+	for i, row := range rows {
+		if len(row) < 2 {
+			log.Printf("Baris %d: jumlah kolom tidak valid", i+1)
+			continue
+		}
+
+		kode := strings.TrimSpace(row[0])
+		stok, err := strconv.Atoi(strings.TrimSpace(row[1]))
+		if kode == "" || err != nil {
+			log.Printf("Baris %d: kode produk atau stok fisik tidak valid", i+1)
+			continue
+		}
+
+		// Kolom pertama boleh berisi ID produk atau barcode
+		items = append(items, domain.OpnameItem{
+			IDProduk:   kode,
+			KodeProduk: kode,
+			StokFisik:  stok,
+		})
+	}
+
+	return d.submitCounts(c, id, items)
+}
+
+func (d *HttpDeliveryOpname) submitCounts(c *fiber.Ctx, id string, items []domain.OpnameItem) error {
+	if len(items) == 0 {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "Tidak ada hasil hitung yang valid",
+		})
+	}
+
+	data, err := d.HTTP.SubmitCounts(userContext(c), id, items)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "Hasil hitung berhasil disimpan",
+		"data":    data,
+	})
+}
+
+func (d *HttpDeliveryOpname) GetVariance(c *fiber.Ctx) error {
+	id := c.Params("id_opname")
+	if id == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID opname diperlukan",
+		})
+	}
+
+	data, err := d.HTTP.GetVariance(context.Background(), id)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "Laporan selisih opname",
+		"data":    data,
+	})
+}
+
+func (d *HttpDeliveryOpname) Approve(c *fiber.Ctx) error {
+	id := c.Params("id_opname")
+	if id == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID opname diperlukan",
+		})
+	}
+
+	data, err := d.HTTP.Approve(userContext(c), id)
+	if err != nil {
+		log.Printf("Error approving opname %s: %v", id, err)
+		var stockErr *domain.InsufficientStockError
+		if errors.As(err, &stockErr) {
+			return c.Status(http.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+				"data":  stockErr,
+			})
+		}
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "Opname berhasil disetujui",
+		"data":    data,
+	})
+}
@@ -2,7 +2,9 @@ package delivery
 
 import (
 	"SIE-SRC/domain"
+	"SIE-SRC/middleware"
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -21,6 +23,7 @@ func NewHttpDeliveryPenjualan(app fiber.Router, HTTP domain.PenjualanUseCase) {
 	}
 
 	group := app.Group("/penjualan")
+	group.Use(middleware.OptionalAuthMiddleware())
 	group.Get("/getall", handler.GetAll)
 	group.Get("/by-id/:id_penjualan", handler.GetByID)
 	group.Post("/create", handler.CreateBulk)
@@ -101,8 +104,16 @@ func (d *HttpDeliveryPenjualan) CreateBulk(c *fiber.Ctx) error {
 	}
 
 	// Create the sales records
-	result, err := d.HTTP.CreateBulk(c.Context(), penjualanList)
+	result, err := d.HTTP.CreateBulk(userContext(c), penjualanList)
 	if err != nil {
+		var stockErr *domain.InsufficientStockError
+		if errors.As(err, &stockErr) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"message": "Stok tidak mencukupi",
+				"error":   err.Error(),
+				"data":    stockErr,
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"message": "Failed to create sales records",
 			"error":   err.Error(),
@@ -123,7 +134,7 @@ func (d *HttpDeliveryPenjualan) Delete(c *fiber.Ctx) error {
 		})
 	}
 
-	err := d.HTTP.Delete(context.Background(), id)
+	err := d.HTTP.Delete(userContext(c), id)
 	if err != nil {
 		log.Printf("Error deleting sale %s: %v", id, err)
 		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
@@ -183,17 +194,52 @@ func (d *HttpDeliveryPenjualan) Update(c *fiber.Ctx) error {
 	}
 
 	sale.IDPenjualan = id
-	err := d.HTTP.Update(context.Background(), &sale)
+
+	// If-Match lebih diutamakan daripada field version di body; update tanpa versi ditolak
+	version, ok, err := parseIfMatch(c)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if ok {
+		sale.Version = version
+	} else if sale.Version <= 0 {
+		return c.Status(http.StatusPreconditionRequired).JSON(fiber.Map{
+			"error": errVersionRequired,
+		})
+	}
+
+	err = d.HTTP.Update(userContext(c), &sale)
 	if err != nil {
 		log.Printf("Error updating sale %s: %v", id, err)
+		var conflictErr *domain.VersionConflictError
+		if errors.As(err, &conflictErr) {
+			if current, ok := conflictErr.Current.(*domain.Penjualan); ok {
+				setETag(c, current.Version)
+			}
+			return c.Status(http.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+				"data":  conflictErr.Current,
+			})
+		}
+		var stockErr *domain.InsufficientStockError
+		if errors.As(err, &stockErr) {
+			return c.Status(http.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+				"data":  stockErr,
+			})
+		}
 		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
 		})
 	}
 
+	setETag(c, sale.Version)
 	return c.Status(http.StatusOK).JSON(fiber.Map{
 		"message": "Data penjualan berhasil diperbarui",
 		"id":      id,
+		"version": sale.Version,
 	})
 }
 
@@ -213,6 +259,7 @@ func (d *HttpDeliveryPenjualan) GetByID(c *fiber.Ctx) error {
 		})
 	}
 
+	setETag(c, data.Version)
 	return c.Status(http.StatusOK).JSON(fiber.Map{
 		"message": "Data penjualan ditemukan",
 		"data":    data,
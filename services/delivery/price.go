@@ -0,0 +1,98 @@
+package delivery
+
+import (
+	"SIE-SRC/domain"
+	"SIE-SRC/middleware"
+	"context"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type HttpDeliveryPrice struct {
+	HTTP domain.PriceUseCase
+}
+
+func NewHttpDeliveryPrice(app fiber.Router, HTTP domain.PriceUseCase) {
+	handler := HttpDeliveryPrice{
+		HTTP: HTTP,
+	}
+
+	group := app.Group("/harga")
+	group.Get("/timeline/:id_produk", handler.GetTimeline)
+
+	// Penjadwalan harga hanya untuk admin dan owner
+	schedule := app.Group("/harga/jadwal")
+	schedule.Use(middleware.AuthMiddleware("admin", "owner"))
+	schedule.Get("/", handler.GetPendingSchedules)
+	schedule.Post("/create", handler.CreateSchedule)
+	schedule.Delete("/cancel/:id_jadwal", handler.CancelSchedule)
+}
+
+func (d *HttpDeliveryPrice) GetTimeline(c *fiber.Ctx) error {
+	id := c.Params("id_produk")
+	if id == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID produk diperlukan",
+		})
+	}
+
+	data, err := d.HTTP.GetTimeline(context.Background(), id)
+	if err != nil {
+		return c.Status(http.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"data": data,
+	})
+}
+
+func (d *HttpDeliveryPrice) GetPendingSchedules(c *fiber.Ctx) error {
+	data, err := d.HTTP.GetPendingSchedules(context.Background())
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal untuk mendapatkan Data",
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"data": data,
+	})
+}
+
+func (d *HttpDeliveryPrice) CreateSchedule(c *fiber.Ctx) error {
+	var schedule domain.PriceSchedule
+	if err := c.BodyParser(&schedule); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "Format data tidak valid",
+		})
+	}
+
+	created, err := d.HTTP.CreateSchedule(userContext(c), &schedule)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(http.StatusCreated).JSON(fiber.Map{
+		"message": "Perubahan harga berhasil dijadwalkan",
+		"data":    created,
+	})
+}
+
+func (d *HttpDeliveryPrice) CancelSchedule(c *fiber.Ctx) error {
+	id := c.Params("id_jadwal")
+
+	if err := d.HTTP.CancelSchedule(context.Background(), id); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "Jadwal harga dibatalkan",
+	})
+}
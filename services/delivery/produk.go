@@ -2,17 +2,21 @@ package delivery
 
 import (
 	"SIE-SRC/domain"
+	"SIE-SRC/middleware"
+	"bytes"
 	"context"
-	"encoding/csv"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/asaskevich/govalidator"
 	"github.com/gofiber/fiber/v2"
-	"github.com/xuri/excelize/v2"
 )
 
 type HttpDeliveryProduk struct {
@@ -25,6 +29,7 @@ func NewHttpDeliveryProduk(app fiber.Router, HTTP domain.ProdukUseCase) {
 	}
 
 	group := app.Group("/produk")
+	group.Use(middleware.OptionalAuthMiddleware())
 	group.Post("/createproduk", handler.CreateProduk)
 	group.Get("/getallproduk", handler.GetAllProduk)
 	group.Get("/by-id/:id_produk", handler.GetProdukById)
@@ -32,10 +37,49 @@ func NewHttpDeliveryProduk(app fiber.Router, HTTP domain.ProdukUseCase) {
 	group.Put("/update/:id_produk", handler.UpdateProduk)
 	group.Delete("/delete/:id_produk", handler.DeleteProduk)
 	group.Post("/importdata", handler.ImportProduk)
+
+	// Trash untuk produk yang sudah dihapus
+	group.Get("/trash", handler.GetDeletedProduk)
+	group.Put("/trash/restore", handler.RestoreProduk)
+	group.Put("/trash/restore/:id_produk", handler.RestoreProduk)
+	group.Delete("/trash/purge", handler.PurgeDeletedProduk)
+
+	// Kartu stok per produk
+	group.Get("/kartu-stok/:id_produk", handler.GetStockMovements)
+
+	// Produk yang perlu dipesan ulang
+	group.Get("/low-stock", handler.GetLowStockProduk)
 }
 
 func (d *HttpDeliveryProduk) GetAllProduk(c *fiber.Ctx) error {
-	val, err := d.HTTP.GetAllProduk(context.Background())
+	filter := domain.ProdukFilter{
+		IDSupplier: c.Query("supplier"),
+	}
+
+	var val []domain.Produk
+	var err error
+	if filter == (domain.ProdukFilter{}) {
+		val, err = d.HTTP.GetAllProduk(context.Background())
+	} else {
+		val, err = d.HTTP.FindProduk(context.Background(), filter)
+	}
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal untuk mendapatkan Data",
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"data": val,
+	})
+}
+
+// GetLowStockProduk menampilkan produk dengan stok di bawah atau sama dengan reorder point
+func (d *HttpDeliveryProduk) GetLowStockProduk(c *fiber.Ctx) error {
+	val, err := d.HTTP.FindProduk(context.Background(), domain.ProdukFilter{
+		IDSupplier: c.Query("supplier"),
+		LowStock:   true,
+	})
 	if err != nil {
 		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Gagal untuk mendapatkan Data",
@@ -59,7 +103,7 @@ func (d *HttpDeliveryProduk) CreateProduk(c *fiber.Ctx) error {
 	// Logging untuk debugging
 	log.Printf("Received data: %+v", product)
 
-	createdProduct, err := d.HTTP.CreateProduk(context.Background(), &product)
+	createdProduct, err := d.HTTP.CreateProduk(userContext(c), &product)
 	if err != nil {
 		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
 			"error":   "Failed to create product",
@@ -88,6 +132,7 @@ func (d *HttpDeliveryProduk) GetProdukById(c *fiber.Ctx) error {
 		})
 	}
 
+	setETag(c, data.Version)
 	return c.Status(http.StatusOK).JSON(fiber.Map{
 		"message": "Data ditemukan",
 		"data":    data,
@@ -140,15 +185,42 @@ func (d *HttpDeliveryProduk) UpdateProduk(c *fiber.Ctx) error {
 
 	body.IDProduk = id
 
-	err := d.HTTP.UpdateProduk(context.Background(), body)
+	// If-Match lebih diutamakan daripada field version di body; update tanpa versi ditolak
+	version, ok, err := parseIfMatch(c)
 	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if ok {
+		body.Version = version
+	} else if body.Version <= 0 {
+		return c.Status(http.StatusPreconditionRequired).JSON(fiber.Map{
+			"error": errVersionRequired,
+		})
+	}
+
+	err = d.HTTP.UpdateProduk(userContext(c), body)
+	if err != nil {
+		var conflictErr *domain.VersionConflictError
+		if errors.As(err, &conflictErr) {
+			if current, ok := conflictErr.Current.(*domain.Produk); ok {
+				setETag(c, current.Version)
+			}
+			return c.Status(http.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+				"data":  conflictErr.Current,
+			})
+		}
 		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Gagal untuk memperbarui data",
 		})
 	}
 
+	setETag(c, body.Version)
 	return c.Status(http.StatusOK).JSON(fiber.Map{
 		"message": "Data berhasil diperbarui",
+		"version": body.Version,
 	})
 }
 
@@ -173,100 +245,149 @@ func (d *HttpDeliveryProduk) DeleteProduk(c *fiber.Ctx) error {
 	})
 }
 
-func (d *HttpDeliveryProduk) ImportProduk(c *fiber.Ctx) error {
-	// Ambil file dari request
-	fileHeader, err := c.FormFile("file")
+func (d *HttpDeliveryProduk) GetDeletedProduk(c *fiber.Ctx) error {
+	val, err := d.HTTP.GetDeletedProduk(context.Background())
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "File tidak ditemukan",
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal untuk mendapatkan Data",
 		})
 	}
 
-	// Buka file
-	file, err := fileHeader.Open()
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"data": val,
+	})
+}
+
+func (d *HttpDeliveryProduk) RestoreProduk(c *fiber.Ctx) error {
+	var body struct {
+		IDs []string `json:"ids"`
+	}
+
+	if id := c.Params("id_produk"); id != "" {
+		body.IDs = []string{id}
+	} else if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "Gagal untuk mem-parsing request body",
+		})
+	}
+
+	if len(body.IDs) == 0 {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID produk diperlukan",
+		})
+	}
+
+	restored, err := d.HTTP.RestoreProduk(context.Background(), body.IDs)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Gagal membuka file",
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal untuk mengembalikan data: " + err.Error(),
 		})
 	}
-	defer file.Close()
 
-	var produkList []domain.Produk
-	filename := strings.ToLower(fileHeader.Filename)
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"message":  "Data berhasil dikembalikan",
+		"restored": restored,
+	})
+}
 
-	// Proses file berdasarkan ekstensi
-	switch {
-	case strings.HasSuffix(filename, ".csv"):
-		// Baca file CSV
-		reader := csv.NewReader(file)
-		reader.FieldsPerRecord = -1 // Izinkan jumlah kolom fleksibel
+func (d *HttpDeliveryProduk) PurgeDeletedProduk(c *fiber.Ctx) error {
+	beforeStr := c.Query("before")
+	if beforeStr == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "Parameter before diperlukan (format YYYY-MM-DD)",
+		})
+	}
 
-		records, err := reader.ReadAll()
-		if err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": "Gagal membaca file CSV",
-			})
-		}
+	before, err := time.ParseInLocation("2006-01-02", beforeStr, time.Local)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "Format tanggal tidak valid, gunakan YYYY-MM-DD",
+		})
+	}
 
-		// Skip header row
-		if len(records) > 1 {
-			records = records[1:]
-		}
+	result, err := d.HTTP.PurgeDeletedProduk(context.Background(), before)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal untuk menghapus permanen data: " + err.Error(),
+		})
+	}
 
-		produkList = make([]domain.Produk, 0, len(records))
-		for i, record := range records {
-			if len(record) < 6 { // Minimal harus ada 6 kolom
-				log.Printf("Baris %d: jumlah kolom tidak valid", i+1)
-				continue
-			}
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "Data berhasil dihapus permanen",
+		"data":    result,
+	})
+}
 
-			// Bersihkan dan konversi harga ke int
-			hargaStr := strings.TrimSpace(strings.ReplaceAll(record[4], ",", ""))
-			hargaFloat, err := strconv.ParseFloat(hargaStr, 64)
-			if err != nil {
-				log.Printf("Baris %d: harga tidak valid", i+1)
-				continue
-			}
-			harga := int(hargaFloat)
+func (d *HttpDeliveryProduk) GetStockMovements(c *fiber.Ctx) error {
+	id := c.Params("id_produk")
+	if id == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID produk diperlukan",
+		})
+	}
 
-			stok, err := strconv.Atoi(strings.TrimSpace(record[5]))
-			if err != nil {
-				log.Printf("Baris %d: stok tidak valid", i+1)
-				continue
-			}
+	from, to, err := parseDateRange(c)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
 
-			produk := domain.Produk{
-				NamaProduk:  strings.TrimSpace(record[0]),
-				Kategori:    strings.TrimSpace(record[1]),
-				SubKategori: strings.TrimSpace(record[2]),
-				KodeProduk:  strings.TrimSpace(record[3]),
-				Harga:       harga,
-				Stok:        stok,
-			}
-			produkList = append(produkList, produk)
-		}
+	data, err := d.HTTP.GetStockMovements(context.Background(), id, from, to)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal untuk mendapatkan kartu stok: " + err.Error(),
+		})
+	}
 
-	case strings.HasSuffix(filename, ".xlsx"):
-		// Baca file Excel
-		xlsx, err := excelize.OpenReader(file)
-		if err != nil {
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "Data ditemukan",
+		"data":    data,
+	})
+}
+
+func (d *HttpDeliveryProduk) ImportProduk(c *fiber.Ctx) error {
+	// Ambil file dari request
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		// Tanpa file, terima body mentah berupa JSON array atau NDJSON
+		if !isJSONContentType(c.Get(fiber.HeaderContentType)) {
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": "Gagal membaca file Excel",
+				"error": "File tidak ditemukan",
 			})
 		}
 
-		// Ambil sheet pertama
-		sheetName := xlsx.GetSheetName(0)
-		rows, err := xlsx.GetRows(sheetName)
+		produkList, err := parseProdukJSON(bytes.NewReader(c.Body()))
 		if err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": "Gagal membaca sheet Excel",
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": fmt.Sprintf("Gagal membaca data JSON: %v", err),
 			})
 		}
 
-		// Skip header row
-		if len(rows) > 1 {
-			rows = rows[1:]
+		return d.importProdukList(c, produkList)
+	}
+
+	// Buka file
+	file, err := fileHeader.Open()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal membuka file",
+		})
+	}
+	defer file.Close()
+
+	var produkList []domain.Produk
+	filename := strings.ToLower(fileHeader.Filename)
+
+	// Proses file berdasarkan ekstensi
+	switch {
+	case isSpreadsheetFile(filename):
+		// Baca file CSV atau Excel
+		rows, err := readSpreadsheetRows(file, filename)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
 		}
 
 		produkList = make([]domain.Produk, 0, len(rows))
@@ -277,13 +398,11 @@ func (d *HttpDeliveryProduk) ImportProduk(c *fiber.Ctx) error {
 			}
 
 			// Bersihkan dan konversi harga ke int
-			hargaStr := strings.TrimSpace(strings.ReplaceAll(row[4], ",", ""))
-			hargaFloat, err := strconv.ParseFloat(hargaStr, 64)
+			harga, err := parseHargaCell(row[4])
 			if err != nil {
 				log.Printf("Baris %d: harga tidak valid", i+1)
 				continue
 			}
-			harga := int(hargaFloat)
 
 			stok, err := strconv.Atoi(strings.TrimSpace(row[5]))
 			if err != nil {
@@ -299,15 +418,36 @@ func (d *HttpDeliveryProduk) ImportProduk(c *fiber.Ctx) error {
 				Harga:       harga,
 				Stok:        stok,
 			}
+
+			// Kolom ke-7 opsional: ID supplier utama
+			if len(row) > 6 {
+				produk.IDSupplier = strings.TrimSpace(row[6])
+			}
 			produkList = append(produkList, produk)
 		}
 
+	case strings.HasSuffix(filename, ".json"),
+		strings.HasSuffix(filename, ".ndjson"),
+		strings.HasSuffix(filename, ".jsonl"):
+		// Baca file JSON array atau NDJSON (satu produk per baris)
+		produkList, err = parseProdukJSON(file)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": fmt.Sprintf("Gagal membaca file JSON: %v", err),
+			})
+		}
+
 	default:
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Format file tidak didukung. Gunakan CSV atau XLSX",
+			"error": "Format file tidak didukung. Gunakan CSV, XLSX, JSON atau NDJSON",
 		})
 	}
 
+	return d.importProdukList(c, produkList)
+}
+
+// importProdukList meneruskan hasil parsing ke pipeline ImportData
+func (d *HttpDeliveryProduk) importProdukList(c *fiber.Ctx, produkList []domain.Produk) error {
 	// Validasi jumlah data
 	if len(produkList) == 0 {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
@@ -316,7 +456,7 @@ func (d *HttpDeliveryProduk) ImportProduk(c *fiber.Ctx) error {
 	}
 
 	// Import data ke database
-	err = d.HTTP.ImportData(c.Context(), produkList)
+	err := d.HTTP.ImportData(domain.ContextWithUser(c.Context(), middleware.CurrentUsername(c)), produkList)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": fmt.Sprintf("Gagal mengimpor data: %v", err),
@@ -327,3 +467,50 @@ func (d *HttpDeliveryProduk) ImportProduk(c *fiber.Ctx) error {
 		"message": fmt.Sprintf("Berhasil mengimpor %d produk", len(produkList)),
 	})
 }
+
+// isJSONContentType mengecek apakah body request berupa JSON atau NDJSON
+func isJSONContentType(contentType string) bool {
+	contentType = strings.ToLower(contentType)
+	return strings.HasPrefix(contentType, fiber.MIMEApplicationJSON) ||
+		strings.HasPrefix(contentType, "application/x-ndjson") ||
+		strings.HasPrefix(contentType, "application/jsonl")
+}
+
+// parseProdukJSON membaca JSON array produk atau NDJSON (satu objek per baris)
+func parseProdukJSON(r io.Reader) ([]domain.Produk, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Buang BOM UTF-8 dan whitespace di awal
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, fmt.Errorf("data kosong")
+	}
+
+	if data[0] == '[' {
+		var produkList []domain.Produk
+		if err := json.Unmarshal(data, &produkList); err != nil {
+			return nil, err
+		}
+		return produkList, nil
+	}
+
+	produkList := make([]domain.Produk, 0)
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	for i := 1; ; i++ {
+		var produk domain.Produk
+		err := decoder.Decode(&produk)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("data ke-%d: %v", i, err)
+		}
+		produkList = append(produkList, produk)
+	}
+
+	return produkList, nil
+}
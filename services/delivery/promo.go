@@ -0,0 +1,137 @@
+package delivery
+
+import (
+	"SIE-SRC/domain"
+	"SIE-SRC/middleware"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type HttpDeliveryPromo struct {
+	HTTP domain.PromoUseCase
+}
+
+func NewHttpDeliveryPromo(app fiber.Router, HTTP domain.PromoUseCase) {
+	handler := HttpDeliveryPromo{
+		HTTP: HTTP,
+	}
+
+	group := app.Group("/promo")
+	group.Get("/berlaku", handler.GetBerlaku)
+
+	manage := app.Group("/promo")
+	manage.Use(middleware.AuthMiddleware("admin", "owner"))
+	manage.Get("/getall", handler.GetAll)
+	manage.Get("/by-id/:id_promo", handler.GetByID)
+	manage.Post("/create", handler.Create)
+	manage.Put("/update/:id_promo", handler.Update)
+	manage.Delete("/delete/:id_promo", handler.Delete)
+}
+
+func (d *HttpDeliveryPromo) Create(c *fiber.Ctx) error {
+	var promo domain.Promo
+	if err := c.BodyParser(&promo); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "Format data tidak valid",
+		})
+	}
+
+	created, err := d.HTTP.Create(context.Background(), &promo)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(http.StatusCreated).JSON(fiber.Map{
+		"message": "Promo berhasil dibuat",
+		"data":    created,
+	})
+}
+
+func (d *HttpDeliveryPromo) GetAll(c *fiber.Ctx) error {
+	data, err := d.HTTP.GetAll(context.Background())
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal untuk mendapatkan Data",
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"data": data,
+	})
+}
+
+// GetBerlaku menampilkan promo yang berlaku sekarang atau pada ?at= (RFC3339)
+func (d *HttpDeliveryPromo) GetBerlaku(c *fiber.Ctx) error {
+	at := time.Now()
+	if value := c.Query("at"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+				"error": "Format waktu at tidak valid, gunakan RFC3339",
+			})
+		}
+		at = parsed
+	}
+
+	data, err := d.HTTP.GetBerlaku(context.Background(), at)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal untuk mendapatkan Data",
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"data": data,
+	})
+}
+
+func (d *HttpDeliveryPromo) GetByID(c *fiber.Ctx) error {
+	data, err := d.HTTP.GetByID(context.Background(), c.Params("id_promo"))
+	if err != nil {
+		return c.Status(http.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"data": data,
+	})
+}
+
+func (d *HttpDeliveryPromo) Update(c *fiber.Ctx) error {
+	var promo domain.Promo
+	if err := c.BodyParser(&promo); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "Format data tidak valid",
+		})
+	}
+	promo.IDPromo = c.Params("id_promo")
+
+	if err := d.HTTP.Update(context.Background(), &promo); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "Promo berhasil diperbarui",
+		"data":    promo,
+	})
+}
+
+func (d *HttpDeliveryPromo) Delete(c *fiber.Ctx) error {
+	if err := d.HTTP.Delete(context.Background(), c.Params("id_promo")); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "Promo berhasil dihapus",
+	})
+}
@@ -0,0 +1,178 @@
+package delivery
+
+import (
+	"SIE-SRC/domain"
+	"SIE-SRC/middleware"
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type HttpDeliveryPurchase struct {
+	HTTP domain.PurchaseUseCase
+}
+
+func NewHttpDeliveryPurchase(app fiber.Router, HTTP domain.PurchaseUseCase) {
+	handler := HttpDeliveryPurchase{
+		HTTP: HTTP,
+	}
+
+	group := app.Group("/purchase")
+	group.Use(middleware.OptionalAuthMiddleware())
+	group.Post("/po/create", handler.CreatePO)
+	group.Get("/po/getall", handler.GetAllPO)
+	group.Get("/po/by-id/:id_po", handler.GetPOByID)
+	group.Put("/po/cancel/:id_po", handler.CancelPO)
+	group.Post("/po/receive/:id_po", handler.Receive)
+	group.Get("/receipt/getall", handler.GetAllReceipt)
+	group.Get("/receipt/by-id/:id_penerimaan", handler.GetReceiptByID)
+}
+
+func (d *HttpDeliveryPurchase) CreatePO(c *fiber.Ctx) error {
+	var po domain.PurchaseOrder
+	if err := c.BodyParser(&po); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "Format data tidak valid",
+		})
+	}
+
+	created, err := d.HTTP.CreatePO(userContext(c), &po)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(http.StatusCreated).JSON(fiber.Map{
+		"message": "Purchase order berhasil dibuat",
+		"data":    created,
+	})
+}
+
+func (d *HttpDeliveryPurchase) GetAllPO(c *fiber.Ctx) error {
+	val, err := d.HTTP.GetAllPO(context.Background())
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal untuk mendapatkan Data",
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"data": val,
+	})
+}
+
+func (d *HttpDeliveryPurchase) GetPOByID(c *fiber.Ctx) error {
+	id := c.Params("id_po")
+	if id == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID purchase order diperlukan",
+		})
+	}
+
+	data, err := d.HTTP.GetPOByID(context.Background(), id)
+	if err != nil {
+		return c.Status(http.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "Data ditemukan",
+		"data":    data,
+	})
+}
+
+func (d *HttpDeliveryPurchase) CancelPO(c *fiber.Ctx) error {
+	id := c.Params("id_po")
+	if id == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID purchase order diperlukan",
+		})
+	}
+
+	if err := d.HTTP.CancelPO(context.Background(), id); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "Purchase order berhasil dibatalkan",
+		"id":      id,
+	})
+}
+
+func (d *HttpDeliveryPurchase) Receive(c *fiber.Ctx) error {
+	id := c.Params("id_po")
+	if id == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID purchase order diperlukan",
+		})
+	}
+
+	var receipt domain.GoodsReceipt
+	if err := c.BodyParser(&receipt); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "Format data tidak valid",
+		})
+	}
+
+	for i, item := range receipt.Items {
+		if item.IDProduk == "" || item.Jumlah <= 0 {
+			return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+				"error":     "ID produk dan jumlah diterima wajib diisi",
+				"itemIndex": i,
+			})
+		}
+	}
+
+	created, err := d.HTTP.Receive(userContext(c), id, &receipt)
+	if err != nil {
+		log.Printf("Error receiving PO %s: %v", id, err)
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(http.StatusCreated).JSON(fiber.Map{
+		"message": "Penerimaan barang berhasil dicatat",
+		"data":    created,
+	})
+}
+
+func (d *HttpDeliveryPurchase) GetAllReceipt(c *fiber.Ctx) error {
+	val, err := d.HTTP.GetAllReceipt(context.Background())
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal untuk mendapatkan Data",
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"data": val,
+	})
+}
+
+func (d *HttpDeliveryPurchase) GetReceiptByID(c *fiber.Ctx) error {
+	id := c.Params("id_penerimaan")
+	if id == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID penerimaan diperlukan",
+		})
+	}
+
+	data, err := d.HTTP.GetReceiptByID(context.Background(), id)
+	if err != nil {
+		return c.Status(http.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "Data ditemukan",
+		"data":    data,
+	})
+}
@@ -0,0 +1,60 @@
+package delivery
+
+import (
+	"SIE-SRC/domain"
+	"SIE-SRC/middleware"
+	"context"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type HttpDeliveryQRIS struct {
+	HTTP domain.QRISUseCase
+}
+
+func NewHttpDeliveryQRIS(app fiber.Router, HTTP domain.QRISUseCase) {
+	handler := HttpDeliveryQRIS{
+		HTTP: HTTP,
+	}
+
+	group := app.Group("/penjualan/qris")
+	group.Use(middleware.OptionalAuthMiddleware())
+	group.Get("/:id_penjualan", handler.GetPayload)
+	group.Get("/:id_penjualan/image", handler.GetImage)
+}
+
+// GetPayload menampilkan payload QRIS dinamis untuk penjualan
+func (d *HttpDeliveryQRIS) GetPayload(c *fiber.Ctx) error {
+	data, err := d.HTTP.GetPayload(context.Background(), c.Params("id_penjualan"))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"data": data,
+	})
+}
+
+// GetImage mengembalikan PNG kode QR QRIS (?size= dalam pixel, default 300)
+func (d *HttpDeliveryQRIS) GetImage(c *fiber.Ctx) error {
+	size, ok := queryDimension(c, "size")
+	if !ok {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "Parameter size harus berupa angka positif",
+		})
+	}
+
+	image, err := d.HTTP.GetImage(context.Background(), c.Params("id_penjualan"), size)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	c.Set(fiber.HeaderContentType, "image/png")
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.Status(http.StatusOK).Send(image)
+}
@@ -0,0 +1,46 @@
+package delivery
+
+import (
+	"SIE-SRC/domain"
+	"SIE-SRC/middleware"
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type HttpDeliveryReceipt struct {
+	HTTP domain.ReceiptUseCase
+}
+
+func NewHttpDeliveryReceipt(app fiber.Router, HTTP domain.ReceiptUseCase) {
+	handler := HttpDeliveryReceipt{
+		HTTP: HTTP,
+	}
+
+	group := app.Group("/penjualan")
+	group.Use(middleware.OptionalAuthMiddleware())
+	group.Get("/:id_penjualan/receipt", handler.Render)
+}
+
+// Render mengembalikan struk penjualan (?format=pdf|escpos|text&width=58|80)
+func (d *HttpDeliveryReceipt) Render(c *fiber.Ctx) error {
+	lebar, ok := queryDimension(c, "width")
+	if !ok {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "Parameter width harus 58 atau 80",
+		})
+	}
+
+	receipt, err := d.HTTP.Render(context.Background(), c.Params("id_penjualan"), c.Query("format"), lebar)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	c.Set(fiber.HeaderContentType, receipt.ContentType)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`inline; filename="%s"`, receipt.Filename))
+	return c.Status(http.StatusOK).Send(receipt.Data)
+}
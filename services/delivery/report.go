@@ -0,0 +1,115 @@
+package delivery
+
+import (
+	"SIE-SRC/domain"
+	"SIE-SRC/middleware"
+	"context"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type HttpDeliveryReport struct {
+	HTTP domain.ReportUseCase
+}
+
+func NewHttpDeliveryReport(app fiber.Router, HTTP domain.ReportUseCase) {
+	handler := HttpDeliveryReport{
+		HTTP: HTTP,
+	}
+
+	// Laporan keuangan hanya untuk admin dan owner
+	group := app.Group("/report")
+	group.Use(middleware.AuthMiddleware("admin", "owner"))
+	group.Get("/margin", handler.GetMarginReport)
+	group.Get("/pajak", handler.GetTaxReport)
+	group.Get("/pembayaran", handler.GetPaymentReport)
+}
+
+// GetMarginReport menampilkan margin kotor per produk, induk, kategori, atau periode
+// (?group_by=produk|induk|kategori|periode&interval=day|month&from=YYYY-MM-DD&to=YYYY-MM-DD)
+func (d *HttpDeliveryReport) GetMarginReport(c *fiber.Ctx) error {
+	from, to, err := parseDateRange(c)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	groupBy := c.Query("group_by", domain.ReportGroupProduk)
+	switch groupBy {
+	case domain.ReportGroupProduk, domain.ReportGroupInduk, domain.ReportGroupKategori, domain.ReportGroupPeriode:
+	default:
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "group_by harus produk, induk, kategori, atau periode",
+		})
+	}
+
+	report, err := d.HTTP.GetMarginReport(context.Background(), domain.ReportFilter{
+		From:     from,
+		To:       to,
+		GroupBy:  groupBy,
+		Interval: c.Query("interval"),
+	})
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"data": report,
+	})
+}
+
+// GetTaxReport menampilkan rekap DPP dan PPN per periode dan tarif
+// (?interval=day|month&from=YYYY-MM-DD&to=YYYY-MM-DD)
+func (d *HttpDeliveryReport) GetTaxReport(c *fiber.Ctx) error {
+	from, to, err := parseDateRange(c)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	report, err := d.HTTP.GetTaxReport(context.Background(), domain.ReportFilter{
+		From:     from,
+		To:       to,
+		Interval: c.Query("interval"),
+	})
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"data": report,
+	})
+}
+
+// GetPaymentReport menampilkan penjualan per metode pembayaran
+// (?interval=day|month opsional&from=YYYY-MM-DD&to=YYYY-MM-DD)
+func (d *HttpDeliveryReport) GetPaymentReport(c *fiber.Ctx) error {
+	from, to, err := parseDateRange(c)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	report, err := d.HTTP.GetPaymentReport(context.Background(), domain.ReportFilter{
+		From:     from,
+		To:       to,
+		Interval: c.Query("interval"),
+	})
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"data": report,
+	})
+}
@@ -0,0 +1,242 @@
+package delivery
+
+import (
+	"SIE-SRC/domain"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type HttpDeliverySupplier struct {
+	HTTP domain.SupplierUseCase
+}
+
+func NewHttpDeliverySupplier(app fiber.Router, HTTP domain.SupplierUseCase) {
+	handler := HttpDeliverySupplier{
+		HTTP: HTTP,
+	}
+
+	group := app.Group("/supplier")
+	group.Post("/create", handler.Create)
+	group.Get("/getall", handler.GetAll)
+	group.Get("/by-id/:id_supplier", handler.GetByID)
+	group.Put("/update/:id_supplier", handler.Update)
+	group.Delete("/delete/:id_supplier", handler.Delete)
+	group.Get("/pricelist/:id_supplier", handler.GetPriceList)
+	group.Post("/pricelist/:id_supplier/import", handler.ImportPriceList)
+}
+
+func (d *HttpDeliverySupplier) Create(c *fiber.Ctx) error {
+	var supplier domain.Supplier
+	if err := c.BodyParser(&supplier); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request payload",
+		})
+	}
+
+	if supplier.NamaSupplier == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "Nama supplier diperlukan",
+		})
+	}
+
+	created, err := d.HTTP.Create(context.Background(), &supplier)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal menyimpan supplier: " + err.Error(),
+		})
+	}
+
+	return c.Status(http.StatusCreated).JSON(fiber.Map{
+		"message": "Supplier berhasil dibuat",
+		"data":    created,
+	})
+}
+
+func (d *HttpDeliverySupplier) GetAll(c *fiber.Ctx) error {
+	val, err := d.HTTP.GetAll(context.Background())
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal untuk mendapatkan Data",
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"data": val,
+	})
+}
+
+func (d *HttpDeliverySupplier) GetByID(c *fiber.Ctx) error {
+	id := c.Params("id_supplier")
+	if id == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID supplier diperlukan",
+		})
+	}
+
+	data, err := d.HTTP.GetByID(context.Background(), id)
+	if err != nil {
+		return c.Status(http.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "Data ditemukan",
+		"data":    data,
+	})
+}
+
+func (d *HttpDeliverySupplier) Update(c *fiber.Ctx) error {
+	id := c.Params("id_supplier")
+	if id == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID supplier diperlukan",
+		})
+	}
+
+	var supplier domain.Supplier
+	if err := c.BodyParser(&supplier); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "Gagal untuk mem-parsing request body",
+		})
+	}
+
+	supplier.IDSupplier = id
+	if err := d.HTTP.Update(context.Background(), &supplier); err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal untuk memperbarui data: " + err.Error(),
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "Data berhasil diperbarui",
+	})
+}
+
+func (d *HttpDeliverySupplier) Delete(c *fiber.Ctx) error {
+	id := c.Params("id_supplier")
+	if id == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID supplier diperlukan",
+		})
+	}
+
+	if err := d.HTTP.Delete(context.Background(), id); err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal untuk menghapus data: " + err.Error(),
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "Data berhasil dihapus",
+	})
+}
+
+func (d *HttpDeliverySupplier) GetPriceList(c *fiber.Ctx) error {
+	id := c.Params("id_supplier")
+	if id == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID supplier diperlukan",
+		})
+	}
+
+	data, err := d.HTTP.GetPriceList(context.Background(), id)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal untuk mendapatkan Data",
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"data": data,
+	})
+}
+
+// ImportPriceList mengimpor daftar harga supplier dari file CSV atau XLSX.
+// Kolom: barcode atau ID produk, harga beli, minimal order (opsional).
+func (d *HttpDeliverySupplier) ImportPriceList(c *fiber.Ctx) error {
+	id := c.Params("id_supplier")
+	if id == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID supplier diperlukan",
+		})
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "File tidak ditemukan",
+		})
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal membuka file",
+		})
+	}
+	defer file.Close()
+
+	rows, err := readSpreadsheetRows(file, fileHeader.Filename)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	prices := make([]domain.SupplierPrice, 0, len(rows))
+	for i, row := range rows {
+		if len(row) < 2 {
+			log.Printf("Baris %d: jumlah kolom tidak valid", i+1)
+			continue
+		}
+
+		harga, err := parseHargaCell(row[1])
+		if err != nil {
+			log.Printf("Baris %d: harga beli tidak valid", i+1)
+			continue
+		}
+
+		minOrder := 0
+		if len(row) > 2 && strings.TrimSpace(row[2]) != "" {
+			minOrder, err = strconv.Atoi(strings.TrimSpace(row[2]))
+			if err != nil {
+				log.Printf("Baris %d: minimal order tidak valid", i+1)
+				continue
+			}
+		}
+
+		// Kolom pertama boleh berisi barcode atau ID produk
+		kode := strings.TrimSpace(row[0])
+		prices = append(prices, domain.SupplierPrice{
+			IDProduk:   kode,
+			KodeProduk: kode,
+			HargaBeli:  harga,
+			MinOrder:   minOrder,
+		})
+	}
+
+	if len(prices) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Tidak ada data valid untuk diimpor",
+		})
+	}
+
+	result, err := d.HTTP.ImportPriceList(context.Background(), id, prices)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": fmt.Sprintf("Gagal mengimpor daftar harga: %v", err),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": fmt.Sprintf("Berhasil mengimpor %d harga", result.Imported),
+		"data":    result,
+	})
+}
@@ -0,0 +1,53 @@
+package notification
+
+import (
+	"SIE-SRC/config"
+	"SIE-SRC/domain"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/pandeptwidyaop/golog"
+)
+
+type slackNotifier struct {
+	enabled bool
+}
+
+// InitSlack mengkonfigurasi golog.Slack dari environment.
+// Tanpa SLACK_WEBHOOK_URL notifikasi hanya ditulis ke log.
+func InitSlack() {
+	url := config.GetSlackWebhookURL()
+	if url == "" {
+		log.Println("SLACK_WEBHOOK_URL tidak diatur, notifikasi Slack dinonaktifkan")
+		return
+	}
+	golog.NewCustomInstance(config.GetSlackChannel(), config.GetSlackUsername(), url)
+}
+
+// NewSlackNotifier mengirim notifikasi lewat webhook Slack (atau yang kompatibel) milik golog
+func NewSlackNotifier() domain.Notifier {
+	return &slackNotifier{
+		enabled: config.GetSlackWebhookURL() != "",
+	}
+}
+
+// NotifyLowStock dikirim di goroutine terpisah agar transaksi penjualan tidak menunggu webhook
+func (n *slackNotifier) NotifyLowStock(ctx context.Context, event domain.LowStockEvent) error {
+	message := fmt.Sprintf("Stok %s (%s) tinggal %d, di bawah reorder point %d. Saran pemesanan: %d",
+		event.NamaProduk, event.IDProduk, event.Stok, event.ReorderPoint, event.ReorderQty)
+	log.Println(message)
+
+	if !n.enabled {
+		return nil
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("gagal menyusun notifikasi stok: %v", err)
+	}
+
+	go golog.Slack.WarningWithData(message, data, nil)
+	return nil
+}
@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"SIE-SRC/domain"
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoRepoStockBatch struct {
+	DB *mongo.Database
+}
+
+func NewMongoRepoStockBatch(client *mongo.Database) domain.StockBatchRepository {
+	return &mongoRepoStockBatch{
+		DB: client,
+	}
+}
+
+var _StockBatch = "stock_batch"
+
+// Create mendaftarkan batch baru. Jumlah seluruh batch aktif tidak boleh melebihi stok produk.
+func (rp *mongoRepoStockBatch) Create(ctx context.Context, bd *domain.StockBatch) (domain.StockBatch, error) {
+	DataBatch := rp.DB.Collection(_StockBatch)
+	DataProduk := rp.DB.Collection(_Produk)
+
+	if bd.IDProduk == "" {
+		return domain.StockBatch{}, fmt.Errorf("id produk tidak boleh kosong")
+	}
+	if bd.Jumlah <= 0 {
+		return domain.StockBatch{}, fmt.Errorf("jumlah batch harus lebih dari 0")
+	}
+	if bd.TanggalKadaluarsa.IsZero() {
+		return domain.StockBatch{}, fmt.Errorf("tanggal kadaluarsa tidak boleh kosong")
+	}
+
+	var produk domain.Produk
+	err := DataProduk.FindOne(ctx, bson.M{"_id": bd.IDProduk, "is_deleted": nil}).Decode(&produk)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return domain.StockBatch{}, fmt.Errorf("produk dengan ID %s tidak ditemukan", bd.IDProduk)
+		}
+		return domain.StockBatch{}, fmt.Errorf("gagal untuk mendapatkan produk: %w", err)
+	}
+
+	batched, err := rp.sumActive(ctx, bd.IDProduk)
+	if err != nil {
+		return domain.StockBatch{}, err
+	}
+	if batched+bd.Jumlah > produk.Stok {
+		return domain.StockBatch{}, fmt.Errorf("jumlah batch melebihi stok produk %s (stok: %d, sudah dalam batch: %d, diminta: %d)",
+			produk.NamaProduk, produk.Stok, batched, bd.Jumlah)
+	}
+
+	now := time.Now()
+	bd.ID = primitive.NewObjectID()
+	bd.NamaProduk = produk.NamaProduk
+	bd.JumlahAwal = bd.Jumlah
+	bd.UpdatedAt = now
+	if bd.TanggalMasuk.IsZero() {
+		bd.TanggalMasuk = now
+	}
+
+	if _, err := DataBatch.InsertOne(ctx, bd); err != nil {
+		return domain.StockBatch{}, fmt.Errorf("gagal menyimpan batch: %w", err)
+	}
+
+	return *bd, nil
+}
+
+// GetByProduk menampilkan batch aktif sebuah produk, urut FEFO
+func (rp *mongoRepoStockBatch) GetByProduk(ctx context.Context, idProduk string) ([]domain.StockBatch, error) {
+	return rp.find(ctx, bson.M{"id_produk": idProduk, "jumlah": bson.M{"$gt": 0}})
+}
+
+// GetExpiring menampilkan batch aktif yang kadaluarsa dalam rentang waktu tertentu,
+// termasuk yang sudah lewat kadaluarsa tetapi masih bersisa
+func (rp *mongoRepoStockBatch) GetExpiring(ctx context.Context, within time.Duration) ([]domain.StockBatch, error) {
+	return rp.find(ctx, bson.M{
+		"jumlah":             bson.M{"$gt": 0},
+		"tanggal_kadaluarsa": bson.M{"$lte": time.Now().Add(within)},
+	})
+}
+
+// ConsumeFEFO mengurangi batch mulai dari tanggal kadaluarsa paling awal
+func (rp *mongoRepoStockBatch) ConsumeFEFO(ctx context.Context, idProduk string, kuantitas int) ([]domain.BatchConsumption, error) {
+	DataBatch := rp.DB.Collection(_StockBatch)
+
+	batches, err := rp.GetByProduk(ctx, idProduk)
+	if err != nil {
+		return nil, err
+	}
+
+	consumptions := make([]domain.BatchConsumption, 0)
+	sisa := kuantitas
+	for _, batch := range batches {
+		if sisa == 0 {
+			break
+		}
+
+		ambil := batch.Jumlah
+		if ambil > sisa {
+			ambil = sisa
+		}
+
+		// Update bersyarat agar batch tidak pernah negatif
+		result, err := DataBatch.UpdateOne(ctx, bson.M{
+			"_id":    batch.ID,
+			"jumlah": bson.M{"$gte": ambil},
+		}, bson.M{
+			"$inc": bson.M{"jumlah": -ambil},
+			"$set": bson.M{"updated_at": time.Now()},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("gagal mengurangi batch %s: %w", batch.NoBatch, err)
+		}
+		if result.MatchedCount == 0 {
+			return nil, fmt.Errorf("batch %s berubah saat diproses, silakan ulangi", batch.NoBatch)
+		}
+
+		consumptions = append(consumptions, domain.BatchConsumption{
+			IDBatch:           batch.ID,
+			NoBatch:           batch.NoBatch,
+			TanggalKadaluarsa: batch.TanggalKadaluarsa,
+			Jumlah:            ambil,
+		})
+		sisa -= ambil
+	}
+
+	return consumptions, nil
+}
+
+// Restore mengembalikan jumlah batch yang sebelumnya diambil (edit atau hapus penjualan)
+func (rp *mongoRepoStockBatch) Restore(ctx context.Context, consumptions []domain.BatchConsumption) error {
+	DataBatch := rp.DB.Collection(_StockBatch)
+
+	for _, consumption := range consumptions {
+		_, err := DataBatch.UpdateOne(ctx, bson.M{"_id": consumption.IDBatch}, bson.M{
+			"$inc": bson.M{"jumlah": consumption.Jumlah},
+			"$set": bson.M{"updated_at": time.Now()},
+		})
+		if err != nil {
+			return fmt.Errorf("gagal mengembalikan batch %s: %v", consumption.NoBatch, err)
+		}
+	}
+
+	return nil
+}
+
+func (rp *mongoRepoStockBatch) find(ctx context.Context, filter bson.M) ([]domain.StockBatch, error) {
+	DataBatch := rp.DB.Collection(_StockBatch)
+
+	opts := options.Find().SetSort(bson.D{
+		{Key: "tanggal_kadaluarsa", Value: 1},
+		{Key: "tanggal_masuk", Value: 1},
+	})
+	cursor, err := DataBatch.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil batch: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	batches := make([]domain.StockBatch, 0)
+	if err := cursor.All(ctx, &batches); err != nil {
+		return nil, fmt.Errorf("gagal membaca batch: %w", err)
+	}
+
+	return batches, nil
+}
+
+// sumActive menghitung total stok produk yang sudah tercatat dalam batch
+func (rp *mongoRepoStockBatch) sumActive(ctx context.Context, idProduk string) (int, error) {
+	batches, err := rp.GetByProduk(ctx, idProduk)
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0
+	for _, batch := range batches {
+		total += batch.Jumlah
+	}
+	return total, nil
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"SIE-SRC/domain"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoRepoCounter struct {
+	DB *mongo.Database
+}
+
+func NewMongoRepoCounter(client *mongo.Database) domain.CounterRepository {
+	return &mongoRepoCounter{
+		DB: client,
+	}
+}
+
+var _Counter = "counters"
+
+// NextSequence menaikkan counter dengan findOneAndUpdate $inc sehingga aman untuk request paralel
+func (rp *mongoRepoCounter) NextSequence(ctx context.Context, name string, n int64) (int64, error) {
+	DataCounter := rp.DB.Collection(_Counter)
+
+	if n <= 0 {
+		return 0, fmt.Errorf("jumlah sequence harus lebih dari 0")
+	}
+
+	opts := options.FindOneAndUpdate().
+		SetUpsert(true).
+		SetReturnDocument(options.After)
+
+	var counter domain.Counter
+	err := DataCounter.FindOneAndUpdate(ctx, bson.M{"_id": name}, bson.M{
+		"$inc": bson.M{"seq": n},
+	}, opts).Decode(&counter)
+	if err != nil {
+		return 0, fmt.Errorf("gagal menaikkan counter %s: %v", name, err)
+	}
+
+	return counter.Seq, nil
+}
+
+// SeedSequence menyetel counter ke nilai seq jika counter masih lebih kecil
+func (rp *mongoRepoCounter) SeedSequence(ctx context.Context, name string, seq int64) error {
+	DataCounter := rp.DB.Collection(_Counter)
+
+	_, err := DataCounter.UpdateOne(ctx, bson.M{"_id": name}, bson.M{
+		"$max": bson.M{"seq": seq},
+	}, options.Update().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("gagal menyetel counter %s: %v", name, err)
+	}
+
+	return nil
+}
+
+// counterSeeder memastikan counter disetel dari data lama sekali per proses
+type counterSeeder struct {
+	mu     sync.Mutex
+	seeded bool
+}
+
+func (s *counterSeeder) ensure(ctx context.Context, seed func(ctx context.Context) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.seeded {
+		return nil
+	}
+	if err := seed(ctx); err != nil {
+		return err
+	}
+	s.seeded = true
+	return nil
+}
+
+// formatSequenceID menyusun ID dari prefix dan nomor urut dengan zero-padding
+func formatSequenceID(format domain.CounterFormat, seq int64) string {
+	return fmt.Sprintf("%s%0*d", format.Prefix, format.Width, seq)
+}
+
+// parseSequenceID mengambil nomor urut dari ID, false jika format tidak sesuai
+func parseSequenceID(format domain.CounterFormat, id string) (int64, bool) {
+	if !strings.HasPrefix(id, format.Prefix) {
+		return 0, false
+	}
+	seq, err := strconv.ParseInt(strings.TrimPrefix(id, format.Prefix), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return seq, true
+}
+
+// seedFromCollection mencari nomor urut tertinggi pada data lama dan menyimpannya ke counter
+func seedFromCollection(ctx context.Context, counter domain.CounterRepository, collection *mongo.Collection,
+	field string, name string, format domain.CounterFormat) error {
+	opts := options.Find().SetProjection(bson.M{field: 1})
+	cursor, err := collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return fmt.Errorf("gagal membaca ID lama: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var maxSeq int64
+	for cursor.Next(ctx) {
+		id, ok := cursor.Current.Lookup(field).StringValueOK()
+		if !ok {
+			continue
+		}
+		if seq, ok := parseSequenceID(format, id); ok && seq > maxSeq {
+			maxSeq = seq
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("gagal membaca ID lama: %v", err)
+	}
+
+	return counter.SeedSequence(ctx, name, maxSeq)
+}
@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"SIE-SRC/domain"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoRepoProdukInduk struct {
+	DB      *mongo.Database
+	Counter domain.CounterRepository
+}
+
+func NewMongoRepoProdukInduk(client *mongo.Database) domain.ProdukIndukRepository {
+	return &mongoRepoProdukInduk{
+		DB:      client,
+		Counter: NewMongoRepoCounter(client),
+	}
+}
+
+var _ProdukInduk = "produk_induk"
+
+var indukIDFormat = domain.CounterFormat{Prefix: "PI", Width: 3}
+
+// normalizeInduk merapikan nama atribut varian dan memvalidasi kategori induk
+func (rp *mongoRepoProdukInduk) normalizeInduk(ctx context.Context, bd *domain.ProdukInduk) error {
+	bd.NamaProduk = strings.TrimSpace(bd.NamaProduk)
+	if bd.NamaProduk == "" {
+		return fmt.Errorf("nama produk induk tidak boleh kosong")
+	}
+
+	seen := make(map[string]bool, len(bd.Atribut))
+	atribut := make([]string, 0, len(bd.Atribut))
+	for _, nama := range bd.Atribut {
+		nama = strings.ToLower(strings.TrimSpace(nama))
+		if nama == "" {
+			return fmt.Errorf("nama atribut varian tidak boleh kosong")
+		}
+		if seen[nama] {
+			return fmt.Errorf("atribut %s muncul lebih dari sekali", nama)
+		}
+		seen[nama] = true
+		atribut = append(atribut, nama)
+	}
+	if len(atribut) == 0 {
+		return fmt.Errorf("minimal harus ada satu atribut varian, misalnya ukuran atau warna")
+	}
+	bd.Atribut = atribut
+
+	index, err := loadKategoriIndex(ctx, rp.DB)
+	if err != nil {
+		return err
+	}
+	bd.Kategori, bd.SubKategori, err = index.resolve(bd.Kategori, bd.SubKategori)
+	return err
+}
+
+// Create menambahkan produk induk baru
+func (rp *mongoRepoProdukInduk) Create(ctx context.Context, bd *domain.ProdukInduk) (domain.ProdukInduk, error) {
+	DataInduk := rp.DB.Collection(_ProdukInduk)
+
+	if err := rp.normalizeInduk(ctx, bd); err != nil {
+		return domain.ProdukInduk{}, err
+	}
+
+	seq, err := rp.Counter.NextSequence(ctx, _ProdukInduk, 1)
+	if err != nil {
+		return domain.ProdukInduk{}, fmt.Errorf("gagal generate ID produk induk: %v", err)
+	}
+	bd.IDInduk = formatSequenceID(indukIDFormat, seq)
+	bd.UpdatedAt = time.Now()
+
+	if _, err := DataInduk.InsertOne(ctx, bd); err != nil {
+		return domain.ProdukInduk{}, fmt.Errorf("gagal menyimpan produk induk: %v", err)
+	}
+
+	return *bd, nil
+}
+
+// GetAll menampilkan semua produk induk urut berdasarkan nama
+func (rp *mongoRepoProdukInduk) GetAll(ctx context.Context) ([]domain.ProdukInduk, error) {
+	DataInduk := rp.DB.Collection(_ProdukInduk)
+
+	cursor, err := DataInduk.Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"nama_produk": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	list := make([]domain.ProdukInduk, 0)
+	if err := cursor.All(ctx, &list); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// GetByID mendapatkan produk induk berdasarkan ID
+func (rp *mongoRepoProdukInduk) GetByID(ctx context.Context, id string) (*domain.ProdukInduk, error) {
+	DataInduk := rp.DB.Collection(_ProdukInduk)
+
+	var induk domain.ProdukInduk
+	err := DataInduk.FindOne(ctx, bson.M{"_id": id}).Decode(&induk)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("produk induk dengan ID %s tidak ditemukan", id)
+		}
+		return nil, fmt.Errorf("gagal mendapatkan produk induk: %v", err)
+	}
+
+	return &induk, nil
+}
+
+// Update memperbarui data induk. Atribut yang masih dipakai varian tidak boleh dihapus.
+func (rp *mongoRepoProdukInduk) Update(ctx context.Context, bd *domain.ProdukInduk) error {
+	DataInduk := rp.DB.Collection(_ProdukInduk)
+
+	if err := rp.normalizeInduk(ctx, bd); err != nil {
+		return err
+	}
+
+	existing, err := rp.GetByID(ctx, bd.IDInduk)
+	if err != nil {
+		return err
+	}
+
+	kept := make(map[string]bool, len(bd.Atribut))
+	for _, nama := range bd.Atribut {
+		kept[nama] = true
+	}
+	for _, nama := range existing.Atribut {
+		if kept[nama] {
+			continue
+		}
+		used, err := rp.DB.Collection(_Produk).CountDocuments(ctx, bson.M{
+			"id_induk":        bd.IDInduk,
+			"is_deleted":      nil,
+			"atribut." + nama: bson.M{"$exists": true},
+		})
+		if err != nil {
+			return fmt.Errorf("gagal memeriksa varian: %v", err)
+		}
+		if used > 0 {
+			return fmt.Errorf("atribut %s masih dipakai %d varian", nama, used)
+		}
+	}
+
+	bd.UpdatedAt = time.Now()
+	_, err = DataInduk.UpdateOne(ctx, bson.M{"_id": bd.IDInduk}, bson.M{
+		"$set": bson.M{
+			"nama_produk":  bd.NamaProduk,
+			"kategori":     bd.Kategori,
+			"sub_kategori": bd.SubKategori,
+			"atribut":      bd.Atribut,
+			"updated_at":   bd.UpdatedAt,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("gagal memperbarui produk induk: %v", err)
+	}
+
+	return nil
+}
+
+// Delete menghapus produk induk yang sudah tidak memiliki varian aktif
+func (rp *mongoRepoProdukInduk) Delete(ctx context.Context, id string) error {
+	DataInduk := rp.DB.Collection(_ProdukInduk)
+
+	count, err := rp.DB.Collection(_Produk).CountDocuments(ctx, bson.M{"id_induk": id, "is_deleted": nil})
+	if err != nil {
+		return fmt.Errorf("gagal memeriksa varian: %v", err)
+	}
+	if count > 0 {
+		return fmt.Errorf("produk induk %s masih memiliki %d varian", id, count)
+	}
+
+	result, err := DataInduk.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return fmt.Errorf("gagal menghapus produk induk: %v", err)
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("produk induk dengan ID %s tidak ditemukan", id)
+	}
+
+	return nil
+}
@@ -0,0 +1,527 @@
+package repository
+
+import (
+	"SIE-SRC/domain"
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoRepoKategori struct {
+	DB      *mongo.Database
+	Counter domain.CounterRepository
+}
+
+func NewMongoRepoKategori(client *mongo.Database) domain.KategoriRepository {
+	return &mongoRepoKategori{
+		DB:      client,
+		Counter: NewMongoRepoCounter(client),
+	}
+}
+
+var _Kategori = "kategori"
+
+var kategoriIDFormat = domain.CounterFormat{Prefix: "KT", Width: 3}
+
+// nameFilter mencocokkan nama tanpa membedakan huruf besar/kecil
+func nameFilter(nama string) primitive.Regex {
+	return primitive.Regex{Pattern: "^" + regexp.QuoteMeta(nama) + "$", Options: "i"}
+}
+
+// parentFilter mengembalikan nilai filter id_parent; kategori utama tidak memiliki id_parent
+func parentFilter(idParent string) interface{} {
+	if idParent == "" {
+		return nil
+	}
+	return idParent
+}
+
+// kategoriIndex adalah pohon kategori di memori untuk validasi field kategori produk
+type kategoriIndex struct {
+	utama map[string]domain.Kategori
+	sub   map[string]map[string]domain.Kategori
+}
+
+func loadKategoriIndex(ctx context.Context, db *mongo.Database) (*kategoriIndex, error) {
+	cursor, err := db.Collection(_Kategori).Find(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil kategori: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var list []domain.Kategori
+	if err := cursor.All(ctx, &list); err != nil {
+		return nil, fmt.Errorf("gagal membaca kategori: %v", err)
+	}
+
+	index := &kategoriIndex{
+		utama: make(map[string]domain.Kategori),
+		sub:   make(map[string]map[string]domain.Kategori),
+	}
+	for _, item := range list {
+		if item.IDParent == "" {
+			index.utama[strings.ToLower(item.Nama)] = item
+		}
+	}
+	for _, item := range list {
+		if item.IDParent == "" {
+			continue
+		}
+		if index.sub[item.IDParent] == nil {
+			index.sub[item.IDParent] = make(map[string]domain.Kategori)
+		}
+		index.sub[item.IDParent][strings.ToLower(item.Nama)] = item
+	}
+
+	return index, nil
+}
+
+// resolve memvalidasi kategori dan sub kategori produk dan mengembalikan penulisan bakunya.
+// Selama pohon kategori masih kosong, nilai apa pun diterima agar data lama tetap bisa disimpan.
+func (idx *kategoriIndex) resolve(kategori, subKategori string) (string, string, error) {
+	kategori = strings.TrimSpace(kategori)
+	subKategori = strings.TrimSpace(subKategori)
+
+	if len(idx.utama) == 0 || (kategori == "" && subKategori == "") {
+		return kategori, subKategori, nil
+	}
+	if kategori == "" {
+		return "", "", fmt.Errorf("sub kategori %s diisi tanpa kategori", subKategori)
+	}
+
+	utama, ok := idx.utama[strings.ToLower(kategori)]
+	if !ok {
+		return "", "", fmt.Errorf("kategori %s tidak terdaftar", kategori)
+	}
+	if subKategori == "" {
+		return utama.Nama, "", nil
+	}
+
+	sub, ok := idx.sub[utama.IDKategori][strings.ToLower(subKategori)]
+	if !ok {
+		return "", "", fmt.Errorf("sub kategori %s tidak terdaftar di kategori %s", subKategori, utama.Nama)
+	}
+
+	return utama.Nama, sub.Nama, nil
+}
+
+// tarifPajak mencari tarif PPN dari sub kategori lalu kategori utama, nil jika keduanya kosong
+func (idx *kategoriIndex) tarifPajak(kategori, subKategori string) *float64 {
+	utama, ok := idx.utama[strings.ToLower(strings.TrimSpace(kategori))]
+	if !ok {
+		return nil
+	}
+	if sub, ok := idx.sub[utama.IDKategori][strings.ToLower(strings.TrimSpace(subKategori))]; ok && sub.TarifPajak != nil {
+		return sub.TarifPajak
+	}
+	return utama.TarifPajak
+}
+
+// Create menambahkan kategori utama atau sub kategori (jika IDParent diisi)
+func (rp *mongoRepoKategori) Create(ctx context.Context, bd *domain.Kategori) (domain.Kategori, error) {
+	DataKategori := rp.DB.Collection(_Kategori)
+
+	bd.Nama = strings.TrimSpace(bd.Nama)
+	if bd.Nama == "" {
+		return domain.Kategori{}, fmt.Errorf("nama kategori tidak boleh kosong")
+	}
+	if err := domain.ValidateTarifPajak(bd.TarifPajak); err != nil {
+		return domain.Kategori{}, err
+	}
+
+	if bd.IDParent != "" {
+		parent, err := rp.GetByID(ctx, bd.IDParent)
+		if err != nil {
+			return domain.Kategori{}, err
+		}
+		if parent.IDParent != "" {
+			return domain.Kategori{}, fmt.Errorf("sub kategori tidak dapat memiliki sub kategori")
+		}
+	}
+
+	if err := rp.checkDuplicate(ctx, bd.Nama, bd.IDParent, ""); err != nil {
+		return domain.Kategori{}, err
+	}
+
+	seq, err := rp.Counter.NextSequence(ctx, _Kategori, 1)
+	if err != nil {
+		return domain.Kategori{}, fmt.Errorf("gagal generate ID kategori: %v", err)
+	}
+	bd.IDKategori = formatSequenceID(kategoriIDFormat, seq)
+	bd.UpdatedAt = time.Now()
+
+	if _, err := DataKategori.InsertOne(ctx, bd); err != nil {
+		return domain.Kategori{}, fmt.Errorf("gagal menyimpan kategori: %v", err)
+	}
+
+	return *bd, nil
+}
+
+// checkDuplicate menolak nama yang sudah dipakai kategori lain dengan parent yang sama
+func (rp *mongoRepoKategori) checkDuplicate(ctx context.Context, nama, idParent, exceptID string) error {
+	filter := bson.M{
+		"nama":      nameFilter(nama),
+		"id_parent": parentFilter(idParent),
+	}
+	if exceptID != "" {
+		filter["_id"] = bson.M{"$ne": exceptID}
+	}
+
+	count, err := rp.DB.Collection(_Kategori).CountDocuments(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("gagal memeriksa nama kategori: %v", err)
+	}
+	if count > 0 {
+		return fmt.Errorf("kategori %s sudah ada", nama)
+	}
+	return nil
+}
+
+// GetAll menampilkan semua kategori urut berdasarkan nama
+func (rp *mongoRepoKategori) GetAll(ctx context.Context) ([]domain.Kategori, error) {
+	DataKategori := rp.DB.Collection(_Kategori)
+
+	opts := options.Find().SetSort(bson.D{{Key: "nama", Value: 1}})
+	cursor, err := DataKategori.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	list := make([]domain.Kategori, 0)
+	if err := cursor.All(ctx, &list); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// GetByID mendapatkan kategori berdasarkan ID
+func (rp *mongoRepoKategori) GetByID(ctx context.Context, id string) (*domain.Kategori, error) {
+	DataKategori := rp.DB.Collection(_Kategori)
+
+	var kategori domain.Kategori
+	err := DataKategori.FindOne(ctx, bson.M{"_id": id}).Decode(&kategori)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("kategori dengan ID %s tidak ditemukan", id)
+		}
+		return nil, fmt.Errorf("gagal mendapatkan kategori: %v", err)
+	}
+
+	return &kategori, nil
+}
+
+// produkFilter mencocokkan produk yang memakai kategori k
+func (rp *mongoRepoKategori) produkFilter(ctx context.Context, k *domain.Kategori) (bson.M, error) {
+	if k.IDParent == "" {
+		return bson.M{"kategori": nameFilter(k.Nama)}, nil
+	}
+
+	parent, err := rp.GetByID(ctx, k.IDParent)
+	if err != nil {
+		return nil, err
+	}
+	return bson.M{
+		"kategori":     nameFilter(parent.Nama),
+		"sub_kategori": nameFilter(k.Nama),
+	}, nil
+}
+
+// updateProdukKategori menerapkan perubahan field kategori ke semua produk dan produk induk
+// yang cocok. Induk ikut diperbarui karena kategorinya disalin ke varian yang tidak mengisi kategori.
+// Jumlah yang dikembalikan adalah jumlah produk yang berubah.
+func (rp *mongoRepoKategori) updateProdukKategori(ctx context.Context, filter bson.M, set bson.M) (int64, error) {
+	set["updated_at"] = time.Now()
+	result, err := rp.DB.Collection(_Produk).UpdateMany(ctx, filter, bson.M{
+		"$set": set,
+		"$inc": bson.M{"version": 1},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("gagal memperbarui kategori produk: %v", err)
+	}
+
+	if _, err := rp.DB.Collection(_ProdukInduk).UpdateMany(ctx, filter, bson.M{"$set": set}); err != nil {
+		return 0, fmt.Errorf("gagal memperbarui kategori produk induk: %v", err)
+	}
+	return result.ModifiedCount, nil
+}
+
+// withTransaction menjalankan fn di dalam transaksi MongoDB
+func (rp *mongoRepoKategori) withTransaction(ctx context.Context, fn func(sc mongo.SessionContext) error) error {
+	sesi, err := rp.DB.Client().StartSession()
+	if err != nil {
+		return fmt.Errorf("gagal memulai sesi: %v", err)
+	}
+	defer sesi.EndSession(ctx)
+
+	err = mongo.WithSession(ctx, sesi, func(sc mongo.SessionContext) error {
+		if err := sesi.StartTransaction(); err != nil {
+			return fmt.Errorf("gagal memulai transaksi: %v", err)
+		}
+		if err := fn(sc); err != nil {
+			return err
+		}
+		return sesi.CommitTransaction(sc)
+	})
+
+	if err != nil {
+		abortErr := sesi.AbortTransaction(ctx)
+		if abortErr != nil {
+			log.Printf("Error saat abort transaksi: %v", abortErr)
+		}
+	}
+	return err
+}
+
+// Rename mengganti nama kategori dan memperbarui produk serta produk induk yang memakainya
+func (rp *mongoRepoKategori) Rename(ctx context.Context, id string, nama string) (int64, error) {
+	nama = strings.TrimSpace(nama)
+	if nama == "" {
+		return 0, fmt.Errorf("nama kategori tidak boleh kosong")
+	}
+
+	var modified int64
+	err := rp.withTransaction(ctx, func(sc mongo.SessionContext) error {
+		kategori, err := rp.GetByID(sc, id)
+		if err != nil {
+			return err
+		}
+		if err := rp.checkDuplicate(sc, nama, kategori.IDParent, id); err != nil {
+			return err
+		}
+
+		filter, err := rp.produkFilter(sc, kategori)
+		if err != nil {
+			return err
+		}
+
+		_, err = rp.DB.Collection(_Kategori).UpdateOne(sc, bson.M{"_id": id}, bson.M{
+			"$set": bson.M{"nama": nama, "updated_at": time.Now()},
+		})
+		if err != nil {
+			return fmt.Errorf("gagal mengganti nama kategori: %v", err)
+		}
+
+		field := "kategori"
+		if kategori.IDParent != "" {
+			field = "sub_kategori"
+		}
+		modified, err = rp.updateProdukKategori(sc, filter, bson.M{field: nama})
+		return err
+	})
+
+	return modified, err
+}
+
+// SetTarifPajak mengubah tarif PPN kategori; nil berarti mengikuti kategori utama atau tarif default
+func (rp *mongoRepoKategori) SetTarifPajak(ctx context.Context, id string, tarif *float64) error {
+	if err := domain.ValidateTarifPajak(tarif); err != nil {
+		return err
+	}
+
+	update := bson.M{"$set": bson.M{"tarif_pajak": tarif, "updated_at": time.Now()}}
+	if tarif == nil {
+		update = bson.M{
+			"$unset": bson.M{"tarif_pajak": ""},
+			"$set":   bson.M{"updated_at": time.Now()},
+		}
+	}
+
+	result, err := rp.DB.Collection(_Kategori).UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return fmt.Errorf("gagal mengubah tarif pajak kategori: %v", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("kategori dengan ID %s tidak ditemukan", id)
+	}
+
+	return nil
+}
+
+// Delete menghapus kategori yang tidak memiliki sub kategori dan tidak dipakai produk maupun produk induk
+func (rp *mongoRepoKategori) Delete(ctx context.Context, id string) error {
+	DataKategori := rp.DB.Collection(_Kategori)
+
+	kategori, err := rp.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	children, err := DataKategori.CountDocuments(ctx, bson.M{"id_parent": id})
+	if err != nil {
+		return fmt.Errorf("gagal memeriksa sub kategori: %v", err)
+	}
+	if children > 0 {
+		return fmt.Errorf("kategori %s masih memiliki %d sub kategori", kategori.Nama, children)
+	}
+
+	filter, err := rp.produkFilter(ctx, kategori)
+	if err != nil {
+		return err
+	}
+	usedInduk, err := rp.DB.Collection(_ProdukInduk).CountDocuments(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("gagal memeriksa produk induk: %v", err)
+	}
+	if usedInduk > 0 {
+		return fmt.Errorf("kategori %s masih dipakai %d produk induk, gunakan merge", kategori.Nama, usedInduk)
+	}
+
+	filter["is_deleted"] = nil
+	used, err := rp.DB.Collection(_Produk).CountDocuments(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("gagal memeriksa produk: %v", err)
+	}
+	if used > 0 {
+		return fmt.Errorf("kategori %s masih dipakai %d produk, gunakan merge", kategori.Nama, used)
+	}
+
+	if _, err := DataKategori.DeleteOne(ctx, bson.M{"_id": id}); err != nil {
+		return fmt.Errorf("gagal menghapus kategori: %v", err)
+	}
+
+	return nil
+}
+
+// Merge memindahkan semua produk dari kategori sumber ke kategori tujuan lalu menghapus sumber.
+// Sub kategori dari kategori utama sumber dipindah ke tujuan, atau digabung jika namanya sama.
+func (rp *mongoRepoKategori) Merge(ctx context.Context, sourceID, targetID string) (domain.KategoriMergeResult, error) {
+	if sourceID == targetID {
+		return domain.KategoriMergeResult{}, fmt.Errorf("kategori sumber dan tujuan tidak boleh sama")
+	}
+
+	var result domain.KategoriMergeResult
+	err := rp.withTransaction(ctx, func(sc mongo.SessionContext) error {
+		result = domain.KategoriMergeResult{}
+		DataKategori := rp.DB.Collection(_Kategori)
+
+		source, err := rp.GetByID(sc, sourceID)
+		if err != nil {
+			return err
+		}
+		target, err := rp.GetByID(sc, targetID)
+		if err != nil {
+			return err
+		}
+		if (source.IDParent == "") != (target.IDParent == "") {
+			return fmt.Errorf("kategori utama hanya dapat digabung dengan kategori utama, begitu juga sub kategori")
+		}
+		result.Target = *target
+
+		sourceFilter, err := rp.produkFilter(sc, source)
+		if err != nil {
+			return err
+		}
+
+		if source.IDParent != "" {
+			parent, err := rp.GetByID(sc, target.IDParent)
+			if err != nil {
+				return err
+			}
+			result.ProdukDiubah, err = rp.updateProdukKategori(sc, sourceFilter, bson.M{
+				"kategori":     parent.Nama,
+				"sub_kategori": target.Nama,
+			})
+			if err != nil {
+				return err
+			}
+		} else {
+			result.ProdukDiubah, err = rp.updateProdukKategori(sc, sourceFilter, bson.M{"kategori": target.Nama})
+			if err != nil {
+				return err
+			}
+
+			cursor, err := DataKategori.Find(sc, bson.M{"id_parent": sourceID})
+			if err != nil {
+				return fmt.Errorf("gagal mengambil sub kategori: %v", err)
+			}
+			var children []domain.Kategori
+			if err := cursor.All(sc, &children); err != nil {
+				return fmt.Errorf("gagal membaca sub kategori: %v", err)
+			}
+
+			for _, child := range children {
+				var existing domain.Kategori
+				err := DataKategori.FindOne(sc, bson.M{
+					"id_parent": targetID,
+					"nama":      nameFilter(child.Nama),
+				}).Decode(&existing)
+				if err == mongo.ErrNoDocuments {
+					_, err = DataKategori.UpdateOne(sc, bson.M{"_id": child.IDKategori}, bson.M{
+						"$set": bson.M{"id_parent": targetID, "updated_at": time.Now()},
+					})
+					if err != nil {
+						return fmt.Errorf("gagal memindahkan sub kategori: %v", err)
+					}
+					result.SubDipindah++
+					continue
+				}
+				if err != nil {
+					return fmt.Errorf("gagal memeriksa sub kategori: %v", err)
+				}
+
+				// Sub kategori dengan nama sama digabung, samakan penulisan nama di produk
+				_, err = rp.updateProdukKategori(sc, bson.M{
+					"kategori":     target.Nama,
+					"sub_kategori": nameFilter(child.Nama),
+				}, bson.M{"sub_kategori": existing.Nama})
+				if err != nil {
+					return err
+				}
+				if _, err := DataKategori.DeleteOne(sc, bson.M{"_id": child.IDKategori}); err != nil {
+					return fmt.Errorf("gagal menghapus sub kategori: %v", err)
+				}
+				result.SubDigabung++
+			}
+		}
+
+		if _, err := DataKategori.DeleteOne(sc, bson.M{"_id": sourceID}); err != nil {
+			return fmt.Errorf("gagal menghapus kategori sumber: %v", err)
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+// CountProduk menghitung produk aktif per pasangan kategori dan sub kategori
+func (rp *mongoRepoKategori) CountProduk(ctx context.Context) ([]domain.KategoriCount, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"is_deleted": nil}}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{
+				"kategori":     bson.M{"$toLower": "$kategori"},
+				"sub_kategori": bson.M{"$toLower": "$sub_kategori"},
+			},
+			"jumlah": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":          0,
+			"kategori":     "$_id.kategori",
+			"sub_kategori": "$_id.sub_kategori",
+			"jumlah":       1,
+		}}},
+	}
+
+	cursor, err := rp.DB.Collection(_Produk).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("gagal menghitung produk per kategori: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	counts := make([]domain.KategoriCount, 0)
+	if err := cursor.All(ctx, &counts); err != nil {
+		return nil, fmt.Errorf("gagal membaca jumlah produk per kategori: %v", err)
+	}
+
+	return counts, nil
+}
@@ -0,0 +1,398 @@
+package repository
+
+import (
+	"SIE-SRC/domain"
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoRepoOpname struct {
+	DB         *mongo.Database
+	RepoProduk domain.ProdukRepository
+	RepoBatch  domain.StockBatchRepository
+	Counter    domain.CounterRepository
+}
+
+func NewMongoRepoOpname(client *mongo.Database, produkRepo domain.ProdukRepository) domain.OpnameRepository {
+	return &mongoRepoOpname{
+		DB:         client,
+		RepoProduk: produkRepo,
+		RepoBatch:  NewMongoRepoStockBatch(client),
+		Counter:    NewMongoRepoCounter(client),
+	}
+}
+
+var _Opname = "stock_opname"
+
+var opnameIDFormat = domain.CounterFormat{Prefix: "OP", Width: 3}
+
+// Create membuka sesi opname baru
+func (rp *mongoRepoOpname) Create(ctx context.Context, bd *domain.Opname) (domain.Opname, error) {
+	DataOpname := rp.DB.Collection(_Opname)
+
+	seq, err := rp.Counter.NextSequence(ctx, _Opname, 1)
+	if err != nil {
+		return domain.Opname{}, fmt.Errorf("gagal generate ID opname: %v", err)
+	}
+
+	now := time.Now()
+	bd.IDOpname = formatSequenceID(opnameIDFormat, seq)
+	bd.Status = domain.OpnameStatusOpen
+	bd.Items = []domain.OpnameItem{}
+	bd.DibukaOleh = domain.UserFromContext(ctx)
+	bd.TanggalBuka = now
+	bd.DisetujuiOleh = ""
+	bd.TanggalSetuju = nil
+	bd.UpdatedAt = now
+
+	if _, err := DataOpname.InsertOne(ctx, bd); err != nil {
+		return domain.Opname{}, fmt.Errorf("gagal membuka sesi opname: %v", err)
+	}
+
+	return *bd, nil
+}
+
+// GetAll menampilkan semua sesi opname, terbaru lebih dulu
+func (rp *mongoRepoOpname) GetAll(ctx context.Context) ([]domain.Opname, error) {
+	DataOpname := rp.DB.Collection(_Opname)
+
+	opts := options.Find().SetSort(bson.M{"tanggal_buka": -1})
+	cursor, err := DataOpname.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	list := make([]domain.Opname, 0)
+	if err := cursor.All(ctx, &list); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// GetByID mendapatkan sesi opname berdasarkan ID
+func (rp *mongoRepoOpname) GetByID(ctx context.Context, id string) (*domain.Opname, error) {
+	DataOpname := rp.DB.Collection(_Opname)
+
+	var opname domain.Opname
+	err := DataOpname.FindOne(ctx, bson.M{"_id": id}).Decode(&opname)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("opname dengan ID %s tidak ditemukan", id)
+		}
+		return nil, fmt.Errorf("gagal mengambil data opname: %v", err)
+	}
+
+	return &opname, nil
+}
+
+// SubmitCounts menyimpan hasil hitung fisik. Item dengan produk yang sama akan ditimpa.
+func (rp *mongoRepoOpname) SubmitCounts(ctx context.Context, id string, items []domain.OpnameItem) (*domain.Opname, error) {
+	DataOpname := rp.DB.Collection(_Opname)
+
+	if len(items) == 0 {
+		return nil, fmt.Errorf("minimal harus ada satu item hasil hitung")
+	}
+
+	opname, err := rp.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if opname.Status != domain.OpnameStatusOpen {
+		return nil, fmt.Errorf("opname %s sudah %s, hasil hitung tidak dapat diubah", id, opname.Status)
+	}
+
+	produkMap, err := rp.findProduk(ctx, items)
+	if err != nil {
+		return nil, err
+	}
+
+	// Gabungkan dengan item yang sudah ada berdasarkan ID produk
+	index := make(map[string]int, len(opname.Items))
+	for i, item := range opname.Items {
+		index[item.IDProduk] = i
+	}
+
+	now := time.Now()
+	for i, item := range items {
+		if item.StokFisik < 0 {
+			return nil, fmt.Errorf("stok fisik tidak boleh negatif pada item ke-%d", i+1)
+		}
+
+		// Cocokkan berdasarkan ID produk lebih dulu, lalu barcode
+		produk, ok := produkMap[item.IDProduk]
+		if !ok && item.KodeProduk != "" {
+			produk, ok = produkMap["barcode:"+item.KodeProduk]
+		}
+		if !ok {
+			return nil, fmt.Errorf("produk pada item ke-%d tidak ditemukan (id: %q, barcode: %q)",
+				i+1, item.IDProduk, item.KodeProduk)
+		}
+
+		// Stok sistem dibekukan saat hitung agar transaksi setelahnya tidak ikut dihapus saat approval
+		counted := calculateOpnameItem(domain.OpnameItem{
+			IDProduk:  produk.IDProduk,
+			StokFisik: item.StokFisik,
+		}, produk)
+		counted.DihitungPada = &now
+
+		if idx, exists := index[produk.IDProduk]; exists {
+			opname.Items[idx] = counted
+			continue
+		}
+		index[produk.IDProduk] = len(opname.Items)
+		opname.Items = append(opname.Items, counted)
+	}
+
+	opname.UpdatedAt = now
+	result, err := DataOpname.UpdateOne(ctx, bson.M{"_id": id, "status": domain.OpnameStatusOpen}, bson.M{
+		"$set": bson.M{
+			"items":      opname.Items,
+			"updated_at": opname.UpdatedAt,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("gagal menyimpan hasil hitung: %v", err)
+	}
+	if result.MatchedCount == 0 {
+		return nil, fmt.Errorf("opname %s sudah tidak terbuka", id)
+	}
+
+	return opname, nil
+}
+
+// GetVariance menampilkan selisih hasil hitung terhadap stok sistem saat item dihitung,
+// dan menandai item yang stoknya sudah berubah sejak dihitung
+func (rp *mongoRepoOpname) GetVariance(ctx context.Context, id string) (*domain.OpnameVariance, error) {
+	opname, err := rp.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Opname yang sudah disetujui memakai angka yang dibekukan saat approval
+	if opname.Status == domain.OpnameStatusApproved {
+		return buildOpnameVariance(opname), nil
+	}
+
+	produkMap, err := rp.findProduk(ctx, opname.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, item := range opname.Items {
+		produk, ok := produkMap[item.IDProduk]
+		if !ok {
+			return nil, fmt.Errorf("produk %s pada opname tidak ditemukan", item.IDProduk)
+		}
+		opname.Items[i] = refreshOpnameItem(item, produk)
+	}
+
+	return buildOpnameVariance(opname), nil
+}
+
+// Approve memposting selisih opname ke stok produk dalam satu transaksi
+func (rp *mongoRepoOpname) Approve(ctx context.Context, id string) (*domain.OpnameVariance, error) {
+	DataOpname := rp.DB.Collection(_Opname)
+	DataProduk := rp.DB.Collection(_Produk)
+
+	var report *domain.OpnameVariance
+
+	sesi, err := rp.DB.Client().StartSession()
+	if err != nil {
+		return nil, fmt.Errorf("gagal memulai sesi: %v", err)
+	}
+	defer sesi.EndSession(ctx)
+
+	txCtx, outbox := withLowStockOutbox(ctx)
+	err = mongo.WithSession(txCtx, sesi, func(sc mongo.SessionContext) error {
+		if err := sesi.StartTransaction(); err != nil {
+			return fmt.Errorf("gagal memulai transaksi: %v", err)
+		}
+
+		var opname domain.Opname
+		err := DataOpname.FindOne(sc, bson.M{"_id": id}).Decode(&opname)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				return fmt.Errorf("opname dengan ID %s tidak ditemukan", id)
+			}
+			return fmt.Errorf("gagal mengambil data opname: %v", err)
+		}
+
+		if opname.Status != domain.OpnameStatusOpen {
+			return fmt.Errorf("opname %s sudah %s", id, opname.Status)
+		}
+		if len(opname.Items) == 0 {
+			return fmt.Errorf("opname %s belum memiliki hasil hitung", id)
+		}
+
+		user := domain.UserFromContext(ctx)
+		ref := domain.StockRef{
+			Reason: domain.StockReasonOpname,
+			RefID:  id,
+			User:   user,
+		}
+
+		for i, item := range opname.Items {
+			// Baca stok terkini di dalam transaksi
+			var produk domain.Produk
+			err := DataProduk.FindOne(sc, bson.M{"_id": item.IDProduk}).Decode(&produk)
+			if err != nil {
+				return fmt.Errorf("gagal mendapatkan produk %s: %v", item.IDProduk, err)
+			}
+
+			// Selisih yang dibekukan saat hitung diposting sebagai delta terhadap stok terkini
+			item = refreshOpnameItem(item, produk)
+			opname.Items[i] = item
+
+			// Selisih adalah penyesuaian, bukan penjualan: tetap diposting walaupun penjualan
+			// setelah hitung fisik membuat stok terkini lebih kecil dari selisihnya
+			if item.Selisih != 0 {
+				err = rp.RepoProduk.AdjustProdukStock(sc, item.IDProduk, item.Selisih, ref)
+			}
+			if err == nil && item.Selisih < 0 {
+				// Barang yang hilang atau rusak diambil dari batch terdekat kadaluarsa
+				_, err = rp.RepoBatch.ConsumeFEFO(sc, item.IDProduk, -item.Selisih)
+			}
+			if err != nil {
+				return fmt.Errorf("gagal menyesuaikan stok produk %s: %w", item.IDProduk, err)
+			}
+		}
+
+		now := time.Now()
+		opname.Status = domain.OpnameStatusApproved
+		opname.DisetujuiOleh = user
+		opname.TanggalSetuju = &now
+		opname.UpdatedAt = now
+
+		result, err := DataOpname.UpdateOne(sc, bson.M{"_id": id, "status": domain.OpnameStatusOpen}, bson.M{
+			"$set": bson.M{
+				"items":          opname.Items,
+				"status":         opname.Status,
+				"disetujui_oleh": opname.DisetujuiOleh,
+				"tanggal_setuju": opname.TanggalSetuju,
+				"updated_at":     opname.UpdatedAt,
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("gagal menyimpan opname: %v", err)
+		}
+		if result.MatchedCount == 0 {
+			return fmt.Errorf("opname %s sudah tidak terbuka", id)
+		}
+
+		if err := sesi.CommitTransaction(sc); err != nil {
+			return fmt.Errorf("gagal commit transaksi: %v", err)
+		}
+
+		report = buildOpnameVariance(&opname)
+		return nil
+	})
+
+	if err != nil {
+		abortErr := sesi.AbortTransaction(ctx)
+		if abortErr != nil {
+			log.Printf("Error saat abort transaksi: %v", abortErr)
+		}
+		return nil, err
+	}
+
+	outbox.flush(ctx)
+	return report, nil
+}
+
+// findProduk mencari produk item opname berdasarkan ID atau barcode.
+// Map berisi kunci ID produk dan "barcode:<kode>".
+func (rp *mongoRepoOpname) findProduk(ctx context.Context, items []domain.OpnameItem) (map[string]domain.Produk, error) {
+	DataProduk := rp.DB.Collection(_Produk)
+
+	ids := make([]string, 0, len(items))
+	barcodes := make([]string, 0)
+	for _, item := range items {
+		if item.IDProduk != "" {
+			ids = append(ids, item.IDProduk)
+		}
+		if item.KodeProduk != "" {
+			barcodes = append(barcodes, item.KodeProduk)
+		}
+	}
+
+	result := make(map[string]domain.Produk, len(items))
+	if len(ids) == 0 && len(barcodes) == 0 {
+		return result, nil
+	}
+
+	// Produk paket tidak memiliki stok fisik sendiri sehingga tidak ikut opname
+	cursor, err := DataProduk.Find(ctx, bson.M{
+		"is_deleted": nil,
+		"komponen.0": bson.M{"$exists": false},
+		"$or": bson.A{
+			bson.M{"_id": bson.M{"$in": ids}},
+			bson.M{"barcode_produk": bson.M{"$in": barcodes}},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("gagal mencari produk: %v", err)
+	}
+
+	var products []domain.Produk
+	if err := cursor.All(ctx, &products); err != nil {
+		return nil, fmt.Errorf("gagal membaca produk: %v", err)
+	}
+
+	for _, produk := range products {
+		result[produk.IDProduk] = produk
+		if produk.KodeProduk != "" {
+			result["barcode:"+produk.KodeProduk] = produk
+		}
+	}
+
+	return result, nil
+}
+
+// calculateOpnameItem mengisi stok sistem dan selisih item berdasarkan data produk
+func calculateOpnameItem(item domain.OpnameItem, produk domain.Produk) domain.OpnameItem {
+	item.NamaProduk = produk.NamaProduk
+	item.KodeProduk = produk.KodeProduk
+	item.Harga = produk.Harga
+	item.StokSistem = produk.Stok
+	item.Selisih = item.StokFisik - produk.Stok
+	item.NilaiSelisih = item.Selisih * produk.Harga
+	return item
+}
+
+// refreshOpnameItem menandai item yang stok sistemnya berubah sejak dihitung.
+// Item lama tanpa waktu hitung belum memiliki stok sistem beku sehingga dihitung dari stok saat ini.
+func refreshOpnameItem(item domain.OpnameItem, produk domain.Produk) domain.OpnameItem {
+	if item.DihitungPada == nil {
+		return calculateOpnameItem(item, produk)
+	}
+	item.StokBerubah = produk.Stok != item.StokSistem
+	return item
+}
+
+// buildOpnameVariance merangkum selisih seluruh item opname
+func buildOpnameVariance(opname *domain.Opname) *domain.OpnameVariance {
+	report := &domain.OpnameVariance{
+		IDOpname:   opname.IDOpname,
+		Status:     opname.Status,
+		Items:      opname.Items,
+		JumlahItem: len(opname.Items),
+	}
+
+	for _, item := range opname.Items {
+		if item.Selisih != 0 {
+			report.ItemSelisih++
+		}
+		report.TotalSelisih += item.Selisih
+		report.TotalNilaiSelisih += item.NilaiSelisih
+	}
+
+	return report
+}
@@ -1,61 +1,113 @@
 package repository
 
 import (
+	"SIE-SRC/config"
 	"SIE-SRC/domain"
 	"context"
 	"fmt"
 	"log"
-	"strconv"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type mongoRepoPenjualan struct {
 	DB         *mongo.Database
 	RepoProduk domain.ProdukRepository
+	RepoBatch  domain.StockBatchRepository
+	RepoPromo  domain.PromoRepository
+	Counter    domain.CounterRepository
+	idFormat   domain.CounterFormat
+	idSeeder   *counterSeeder
+	pajak      domain.PajakSetting
 }
 
 func NewMongoRepoPenjualan(client *mongo.Database, produkRepo domain.ProdukRepository) domain.PenjualanRepository {
 	return &mongoRepoPenjualan{
 		DB:         client,
 		RepoProduk: produkRepo,
+		RepoBatch:  NewMongoRepoStockBatch(client),
+		RepoPromo:  NewMongoRepoPromo(client),
+		Counter:    NewMongoRepoCounter(client),
+		idFormat: domain.CounterFormat{
+			Prefix: config.GetPenjualanIDPrefix(),
+			Width:  config.GetPenjualanIDWidth(),
+		},
+		idSeeder: &counterSeeder{},
+		pajak:    config.GetPajakSetting(),
 	}
 }
 
 var _Penjualan = "penjualan"
 
+// seedCounter menyetel counter penjualan dari ID terbesar yang sudah tersimpan
+func (rp *mongoRepoPenjualan) seedCounter(ctx context.Context) error {
+	return rp.idSeeder.ensure(ctx, func(ctx context.Context) error {
+		return seedFromCollection(ctx, rp.Counter, rp.DB.Collection(_Penjualan), "id_penjualan", _Penjualan, rp.idFormat)
+	})
+}
+
 // Generates the next available ID
 func (rp *mongoRepoPenjualan) GenerateNextID(ctx context.Context) (string, error) {
-	ListPenjualan := rp.DB.Collection(_Penjualan)
-
-	opts := options.FindOne().SetSort(bson.M{"id_penjualan": -1})
-	var lastPenjualan domain.Penjualan
+	if err := rp.seedCounter(ctx); err != nil {
+		return "", err
+	}
 
-	err := ListPenjualan.FindOne(ctx, bson.M{}, opts).Decode(&lastPenjualan)
+	seq, err := rp.Counter.NextSequence(ctx, _Penjualan, 1)
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			return "PJ001", nil
+		return "", err
+	}
+
+	return formatSequenceID(rp.idFormat, seq), nil
+}
+
+// reserveIDs memberi ID pada penjualan yang belum memiliki ID dengan satu $inc counter.
+// Dipanggil sebelum transaksi agar dokumen counter tidak ikut transaksi dan menimbulkan
+// write conflict antar kasir; ID dari transaksi yang gagal tidak dipakai ulang.
+func (rp *mongoRepoPenjualan) reserveIDs(ctx context.Context, bd []domain.Penjualan) error {
+	n := 0
+	for i := range bd {
+		if bd[i].IDPenjualan == "" {
+			n++
 		}
-		return "", fmt.Errorf("error finding last penjualan: %v", err)
+	}
+	if n == 0 {
+		return nil
 	}
 
-	numStr := lastPenjualan.IDPenjualan[2:]
-	num, err := strconv.Atoi(numStr)
+	if err := rp.seedCounter(ctx); err != nil {
+		return fmt.Errorf("gagal menyiapkan counter ID: %v", err)
+	}
+	last, err := rp.Counter.NextSequence(ctx, _Penjualan, int64(n))
 	if err != nil {
-		return "", fmt.Errorf("error parsing last ID number: %v", err)
+		return fmt.Errorf("gagal generate ID: %v", err)
 	}
 
-	newID := fmt.Sprintf("PJ%03d", num+1)
-	return newID, nil
+	seq := last - int64(n) + 1
+	for i := range bd {
+		if bd[i].IDPenjualan == "" {
+			bd[i].IDPenjualan = formatSequenceID(rp.idFormat, seq)
+			seq++
+		}
+	}
+	return nil
 }
 
 // Create menambahkan Penjualan baru ke dalam koleksi.
+// Transaksi dijalankan lewat WithTransaction sehingga write conflict antar kasir diulang otomatis.
 func (rp *mongoRepoPenjualan) CreateBulk(ctx context.Context, bd []domain.Penjualan) ([]domain.Penjualan, error) {
 	ListPenjualan := rp.DB.Collection(_Penjualan)
-	var PenjualanDocs []interface{}
+
+	if err := rp.reserveIDs(ctx, bd); err != nil {
+		return nil, err
+	}
+
+	// Pembayaran diisi ulang oleh ApplyPembayaran, simpan input kasir untuk percobaan ulang
+	pembayaran := make([][]domain.Pembayaran, len(bd))
+	for i := range bd {
+		pembayaran[i] = append([]domain.Pembayaran(nil), bd[i].Pembayaran...)
+	}
 
 	sesi, err := rp.DB.Client().StartSession()
 	if err != nil {
@@ -63,87 +115,91 @@ func (rp *mongoRepoPenjualan) CreateBulk(ctx context.Context, bd []domain.Penjua
 	}
 	defer sesi.EndSession(ctx)
 
-	err = mongo.WithSession(ctx, sesi, func(sc mongo.SessionContext) error {
-		if err := sesi.StartTransaction(); err != nil {
-			return fmt.Errorf("gagal memulai transaksi: %v", err)
+	txCtx, outbox := withLowStockOutbox(ctx)
+	_, err = sesi.WithTransaction(txCtx, func(sc mongo.SessionContext) (interface{}, error) {
+		outbox.reset()
+		PenjualanDocs := make([]interface{}, 0, len(bd))
+
+		promos, err := rp.RepoPromo.GetAktif(sc)
+		if err != nil {
+			return nil, err
+		}
+		kategori, err := loadKategoriIndex(sc, rp.DB)
+		if err != nil {
+			return nil, err
 		}
 
 		for i := range bd {
-			if bd[i].IDPenjualan == "" {
-				nextID, err := rp.GenerateNextID(ctx)
-				if err != nil {
-					return fmt.Errorf("gagal generate ID: %v", err)
-				}
-				bd[i].IDPenjualan = nextID
-			}
+			bd[i].Pembayaran = append([]domain.Pembayaran(nil), pembayaran[i]...)
 
+			bd[i].GrupPelanggan = domain.NormalizeGrupPelanggan(bd[i].GrupPelanggan)
 			if bd[i].NamaPenjual == "" {
-				return fmt.Errorf("nama penjual tidak boleh kosong pada data ke-%d", i+1)
+				return nil, fmt.Errorf("nama penjual tidak boleh kosong pada data ke-%d", i+1)
 			}
 
 			if len(bd[i].Produk) == 0 {
-				return fmt.Errorf("minimal harus ada satu produk pada data ke-%d", i+1)
+				return nil, fmt.Errorf("minimal harus ada satu produk pada data ke-%d", i+1)
+			}
+
+			if bd[i].Tanggal.IsZero() {
+				bd[i].Tanggal = time.Now()
 			}
 
-			total := 0
+			produkByID := make(map[string]*domain.Produk, len(bd[i].Produk))
 			for j, item := range bd[i].Produk {
 				if item.IDProduk == "" {
-					return fmt.Errorf("id produk tidak boleh kosong pada produk ke-%d, data ke-%d", j+1, i+1)
+					return nil, fmt.Errorf("id produk tidak boleh kosong pada produk ke-%d, data ke-%d", j+1, i+1)
 				}
 
 				if item.JumlahProduk <= 0 {
-					return fmt.Errorf("jumlah produk harus lebih dari 0 pada produk ke-%d, data ke-%d", j+1, i+1)
+					return nil, fmt.Errorf("jumlah produk harus lebih dari 0 pada produk ke-%d, data ke-%d", j+1, i+1)
 				}
 
 				produk, err := rp.RepoProduk.GetProdukById(sc, item.IDProduk)
 				if err != nil {
-					return fmt.Errorf("gagal mendapatkan info produk: %v", err)
+					return nil, fmt.Errorf("gagal mendapatkan info produk: %w", err)
 				}
 
-				if produk.Stok < item.JumlahProduk {
-					return fmt.Errorf("stok tidak mencukupi untuk produk %s (tersedia: %d, diminta: %d)",
-						produk.NamaProduk, produk.Stok, item.JumlahProduk)
+				line := &bd[i].Produk[j]
+				if err := priceLine(produk, line, bd[i].GrupPelanggan); err != nil {
+					return nil, err
 				}
+				line.TarifPajak = rp.tarifPajak(produk, kategori)
 
-				err = rp.RepoProduk.DecreaseProdukStock(sc, item.IDProduk, item.JumlahProduk)
+				err = rp.deductLine(sc, produk, line, domain.StockRef{
+					Reason: domain.StockReasonSale,
+					RefID:  bd[i].IDPenjualan,
+					User:   stockUser(ctx, &bd[i]),
+				})
 				if err != nil {
-					return fmt.Errorf("gagal mengurangi stok: %v", err)
+					return nil, err
 				}
 
-				bd[i].Produk[j].Harga = produk.Harga
-				bd[i].Produk[j].Subtotal = produk.Harga * item.JumlahProduk
-				total += bd[i].Produk[j].Subtotal
+				produkByID[produk.IDProduk] = produk
 			}
 
-			bd[i].Total = total
-			bd[i].UpdatedAt = time.Now()
-			if bd[i].Tanggal.IsZero() {
-				bd[i].Tanggal = time.Now()
+			bd[i].WaktuPromo = time.Now()
+			applyPromos(&bd[i], produkByID, promos, bd[i].WaktuPromo)
+			applyPajak(&bd[i], rp.pajak)
+			if err := bd[i].ApplyPembayaran(); err != nil {
+				return nil, fmt.Errorf("%v pada data ke-%d", err, i+1)
 			}
+			bd[i].Version = 1
+			bd[i].UpdatedAt = time.Now()
 
 			PenjualanDocs = append(PenjualanDocs, bd[i])
 		}
 
-		_, err := ListPenjualan.InsertMany(sc, PenjualanDocs)
-		if err != nil {
-			return fmt.Errorf("gagal menyimpan data penjualan: %v", err)
-		}
-
-		if err := sesi.CommitTransaction(sc); err != nil {
-			return fmt.Errorf("gagal commit transaksi: %v", err)
+		if _, err := ListPenjualan.InsertMany(sc, PenjualanDocs); err != nil {
+			return nil, fmt.Errorf("gagal menyimpan data penjualan: %w", err)
 		}
-
-		return nil
+		return nil, nil
 	})
-
 	if err != nil {
-		abortErr := sesi.AbortTransaction(ctx)
-		if abortErr != nil {
-			log.Printf("Error saat abort transaksi: %v", abortErr)
-		}
 		return nil, err
 	}
 
+	outbox.flush(ctx)
 	return bd, nil
 }
 
@@ -203,7 +259,8 @@ func (rp *mongoRepoPenjualan) Update(ctx context.Context, bd *domain.Penjualan)
 	}
 	defer sesi.EndSession(ctx)
 
-	err = mongo.WithSession(ctx, sesi, func(sc mongo.SessionContext) error {
+	txCtx, outbox := withLowStockOutbox(ctx)
+	err = mongo.WithSession(txCtx, sesi, func(sc mongo.SessionContext) error {
 		if err := sesi.StartTransaction(); err != nil {
 			return fmt.Errorf("gagal memulai transaksi: %v", err)
 		}
@@ -218,17 +275,46 @@ func (rp *mongoRepoPenjualan) Update(ctx context.Context, bd *domain.Penjualan)
 			return fmt.Errorf("gagal mengambil data penjualan: %v", err)
 		}
 
+		// Tolak update dari versi yang sudah usang; versi 0 hanya untuk data lama tanpa field version
+		if existingSales.Version != bd.Version {
+			return &domain.VersionConflictError{
+				Expected: bd.Version,
+				Current:  &existingSales,
+			}
+		}
+
+		ref := domain.StockRef{
+			Reason: domain.StockReasonSaleEdit,
+			RefID:  bd.IDPenjualan,
+			User:   stockUser(ctx, bd),
+		}
+
 		// Kembalikan stok produk lama
 		for _, item := range existingSales.Produk {
-			err := rp.RepoProduk.IncreaseProdukStock(sc, item.IDProduk, item.JumlahProduk)
-			if err != nil {
-				return fmt.Errorf("gagal mengembalikan stok produk %s: %v", item.IDProduk, err)
+			if err := rp.restoreLine(sc, item, ref); err != nil {
+				return err
 			}
 		}
 
+		// Promo dievaluasi pada waktu transaksi asli sehingga edit setelah promo berakhir
+		// tidak menghapus diskonnya; promo yang dipakai transaksi tetap berlaku walau sudah dinonaktifkan
+		allPromos, err := rp.RepoPromo.GetAll(sc)
+		if err != nil {
+			return err
+		}
+		promos := promosForEdit(allPromos, &existingSales)
+		kategori, err := loadKategoriIndex(sc, rp.DB)
+		if err != nil {
+			return err
+		}
+
 		// Validasi dan update stok baru
-		total := 0
-		for _, item := range bd.Produk {
+		bd.GrupPelanggan = domain.NormalizeGrupPelanggan(bd.GrupPelanggan)
+		if bd.Tanggal.IsZero() {
+			bd.Tanggal = existingSales.Tanggal
+		}
+		produkByID := make(map[string]*domain.Produk, len(bd.Produk))
+		for j, item := range bd.Produk {
 			if item.JumlahProduk <= 0 {
 				return fmt.Errorf("kuantitas produk harus lebih dari 0")
 			}
@@ -238,42 +324,90 @@ func (rp *mongoRepoPenjualan) Update(ctx context.Context, bd *domain.Penjualan)
 				return fmt.Errorf("gagal mendapatkan info produk %s: %v", item.IDProduk, err)
 			}
 
-			if produk.Stok < item.JumlahProduk {
-				return fmt.Errorf("stok produk %s tidak mencukupi (tersedia: %d, diminta: %d)",
-					produk.NamaProduk, produk.Stok, item.JumlahProduk)
+			line := &bd.Produk[j]
+			if err := priceLine(produk, line, bd.GrupPelanggan); err != nil {
+				return err
 			}
+			line.TarifPajak = rp.tarifPajak(produk, kategori)
 
-			err = rp.RepoProduk.DecreaseProdukStock(sc, item.IDProduk, item.JumlahProduk)
-			if err != nil {
-				return fmt.Errorf("gagal mengurangi stok produk %s: %v", item.IDProduk, err)
+			if err := rp.deductLine(sc, produk, line, ref); err != nil {
+				return err
 			}
 
-			total += produk.Harga * item.JumlahProduk
+			produkByID[produk.IDProduk] = produk
 		}
 
 		// Update penjualan
+		bd.WaktuPromo = existingSales.WaktuPromo
+		if bd.WaktuPromo.IsZero() {
+			// Penjualan lama belum menyimpan waktu evaluasi promo
+			bd.WaktuPromo = existingSales.Tanggal
+		}
+		applyPromos(bd, produkByID, promos, bd.WaktuPromo)
+
+		// Mode pajak transaksi lama dipertahankan walaupun pengaturan toko sudah berubah
+		pajak := rp.pajak
+		if existingSales.ModePajak != "" {
+			pajak.Mode = existingSales.ModePajak
+		}
+		applyPajak(bd, pajak)
+
+		// Pembayaran lama dipakai ulang jika tidak dikirim, dan tetap harus menutup total baru
+		if len(bd.Pembayaran) == 0 {
+			bd.Pembayaran = existingSales.Pembayaran
+		}
+		if err := bd.ApplyPembayaran(); err != nil {
+			return err
+		}
 		bd.UpdatedAt = time.Now()
-		bd.Total = total
 
 		update := bson.M{
 			"$set": bson.M{
-				"nama_penjual": bd.NamaPenjual,
-				"tanggal":      bd.Tanggal,
-				"produk":       bd.Produk,
-				"total":        bd.Total,
-				"updated_at":   bd.UpdatedAt,
+				"nama_penjual":   bd.NamaPenjual,
+				"tanggal":        bd.Tanggal,
+				"produk":         bd.Produk,
+				"grup_pelanggan": bd.GrupPelanggan,
+				"subtotal":       bd.Subtotal,
+				"diskon":         bd.Diskon,
+				"promo":          bd.Promo,
+				"mode_pajak":     bd.ModePajak,
+				"dpp":            bd.DPP,
+				"ppn":            bd.PPN,
+				"pajak":          bd.Pajak,
+				"pembayaran":     bd.Pembayaran,
+				"kembalian":      bd.Kembalian,
+				"total":          bd.Total,
+				"updated_at":     bd.UpdatedAt,
 			},
+			"$inc": bson.M{"version": 1},
+		}
+
+		filter := bson.M{"id_penjualan": bd.IDPenjualan, "version": existingSales.Version}
+		if existingSales.Version == 0 {
+			// Data lama yang belum memiliki field version
+			filter["version"] = bson.M{"$in": bson.A{0, nil}}
 		}
 
-		_, err = penjualanProduk.UpdateOne(sc, bson.M{"id_penjualan": bd.IDPenjualan}, update)
+		result, err := penjualanProduk.UpdateOne(sc, filter, update)
 		if err != nil {
 			return fmt.Errorf("gagal update penjualan: %v", err)
 		}
+		if result.MatchedCount == 0 {
+			return &domain.VersionConflictError{
+				Expected: bd.Version,
+				Current:  &existingSales,
+			}
+		}
+		bd.Version = existingSales.Version + 1
 
 		return sesi.CommitTransaction(sc)
 	})
+	if err != nil {
+		return err
+	}
 
-	return err
+	outbox.flush(ctx)
+	return nil
 }
 
 // Delete data penjualan berdasarkan ID.
@@ -302,10 +436,14 @@ func (rp *mongoRepoPenjualan) Delete(ctx context.Context, id string) error {
 		}
 
 		// Kembalikan stok produk
+		ref := domain.StockRef{
+			Reason: domain.StockReasonSaleDelete,
+			RefID:  id,
+			User:   domain.UserFromContext(ctx),
+		}
 		for _, item := range existingSales.Produk {
-			err := rp.RepoProduk.IncreaseProdukStock(sc, item.IDProduk, item.JumlahProduk)
-			if err != nil {
-				return fmt.Errorf("gagal mengembalikan stok produk %s: %v", item.IDProduk, err)
+			if err := rp.restoreLine(sc, item, ref); err != nil {
+				return err
 			}
 		}
 
@@ -320,3 +458,233 @@ func (rp *mongoRepoPenjualan) Delete(ctx context.Context, id string) error {
 
 	return err
 }
+
+// priceLine mengisi satuan, harga, subtotal, dan snapshot HPP sebuah baris penjualan
+// berdasarkan satuan yang dipilih dan tier harga untuk grup pelanggan.
+// Stok selalu dikurangi dalam satuan dasar (lihat JumlahDasar).
+func priceLine(produk *domain.Produk, line *domain.ProdukJual, grup string) error {
+	satuan, err := produk.ResolveSatuan(line.Satuan)
+	if err != nil {
+		return err
+	}
+
+	line.NamaProduk = produk.NamaProduk
+	line.Satuan = satuan.Nama
+	line.Konversi = satuan.Konversi
+	line.Harga = satuan.Harga
+	line.Tier = nil
+
+	// Tier dihitung per satuan dasar dan hanya dipakai jika lebih murah dari harga satuan
+	if tier := produk.ResolveHargaTier(grup, line.JumlahProduk*satuan.Konversi); tier != nil {
+		if harga := tier.Harga * satuan.Konversi; harga < satuan.Harga {
+			line.Harga = harga
+			line.Tier = tier
+		}
+	}
+
+	line.Subtotal = line.Harga * line.JumlahProduk
+	line.HargaPokok = produk.HargaPokok * satuan.Konversi
+	return nil
+}
+
+// applyPromos menerapkan promo yang berlaku pada waktu at, yaitu Penjualan.WaktuPromo (waktu server,
+// bukan tanggal dari klien agar transaksi mundur tidak bisa memakai happy hour yang sudah lewat).
+// Setiap baris mendapat satu promo produk/kategori dengan diskon terbesar (tidak ditumpuk), lalu
+// satu promo transaksi terbesar dihitung dari subtotal setelah diskon baris dan dibagi proporsional
+// ke setiap baris. Subtotal, Diskon, dan Total diisi ulang.
+func applyPromos(bd *domain.Penjualan, produkByID map[string]*domain.Produk, promos []domain.Promo, at time.Time) {
+	berlaku := make([]domain.Promo, 0, len(promos))
+	for _, promo := range promos {
+		if promo.BerlakuPada(at) {
+			berlaku = append(berlaku, promo)
+		}
+	}
+
+	subtotal := 0
+	for j := range bd.Produk {
+		line := &bd.Produk[j]
+		line.Diskon = 0
+		line.Promo = nil
+
+		produk := produkByID[line.IDProduk]
+		for _, promo := range berlaku {
+			if promo.Cakupan == domain.PromoCakupanTransaksi || produk == nil || !promo.CocokProduk(produk) {
+				continue
+			}
+			if diskon := promo.DiskonBaris(*line); diskon > line.Diskon {
+				line.Diskon = diskon
+				line.Promo = &domain.PromoDipakai{IDPromo: promo.IDPromo, Nama: promo.Nama}
+			}
+		}
+
+		subtotal += line.Subtotal - line.Diskon
+	}
+
+	bd.Subtotal = subtotal
+	bd.Diskon = 0
+	bd.Promo = nil
+	for _, promo := range berlaku {
+		if promo.Cakupan != domain.PromoCakupanTransaksi {
+			continue
+		}
+		if diskon := promo.DiskonTransaksi(subtotal); diskon > bd.Diskon {
+			bd.Diskon = diskon
+			bd.Promo = &domain.PromoDipakai{IDPromo: promo.IDPromo, Nama: promo.Nama}
+		}
+	}
+
+	nilai := make([]int, len(bd.Produk))
+	for j, line := range bd.Produk {
+		nilai[j] = line.Subtotal - line.Diskon
+	}
+	for j, potongan := range domain.BagiProporsional(bd.Diskon, nilai) {
+		bd.Produk[j].DiskonTransaksi = potongan
+	}
+
+	bd.Total = bd.Subtotal - bd.Diskon
+}
+
+// promosForEdit memilih promo untuk edit penjualan: promo yang aktif, ditambah promo yang
+// dipakai penjualan lama walaupun sudah dinonaktifkan, karena saat itu promo tersebut berlaku.
+// Promo yang sudah dihapus tidak bisa dihitung ulang.
+func promosForEdit(all []domain.Promo, existing *domain.Penjualan) []domain.Promo {
+	dipakai := make(map[string]bool)
+	if existing.Promo != nil {
+		dipakai[existing.Promo.IDPromo] = true
+	}
+	for _, line := range existing.Produk {
+		if line.Promo != nil {
+			dipakai[line.Promo.IDPromo] = true
+		}
+	}
+
+	promos := make([]domain.Promo, 0, len(all))
+	for _, promo := range all {
+		if dipakai[promo.IDPromo] {
+			promo.Aktif = true
+		}
+		if promo.Aktif {
+			promos = append(promos, promo)
+		}
+	}
+	return promos
+}
+
+// tarifPajak menentukan tarif PPN baris: tarif produk, lalu sub kategori/kategori, lalu tarif default
+func (rp *mongoRepoPenjualan) tarifPajak(produk *domain.Produk, kategori *kategoriIndex) float64 {
+	if produk.TarifPajak != nil {
+		return *produk.TarifPajak
+	}
+	if tarif := kategori.tarifPajak(produk.Kategori, produk.SubKategori); tarif != nil {
+		return *tarif
+	}
+	return rp.pajak.TarifDefault
+}
+
+// applyPajak menghitung DPP dan PPN per tarif dari nilai bersih baris setelah promo,
+// lalu mengisi Total sebagai grand total (ditambah PPN untuk mode eksklusif)
+func applyPajak(bd *domain.Penjualan, setting domain.PajakSetting) {
+	nilaiPerTarif := make(map[float64]int)
+	for _, line := range bd.Produk {
+		nilaiPerTarif[line.TarifPajak] += line.Subtotal - line.Diskon
+	}
+
+	bd.ModePajak = setting.Mode
+	bd.Pajak = setting.HitungPajak(nilaiPerTarif, bd.Diskon)
+	bd.DPP = 0
+	bd.PPN = 0
+	for _, rincian := range bd.Pajak {
+		bd.DPP += rincian.DPP
+		bd.PPN += rincian.PPN
+
+		// DPP tarif dibagi ke barisnya sesuai nilai bersih agar laporan margin tidak memuat PPN
+		indeks := make([]int, 0)
+		nilai := make([]int, 0)
+		for j, line := range bd.Produk {
+			if line.TarifPajak == rincian.Tarif {
+				indeks = append(indeks, j)
+				nilai = append(nilai, line.Subtotal-line.Diskon-line.DiskonTransaksi)
+			}
+		}
+		for k, dpp := range domain.BagiProporsional(rincian.DPP, nilai) {
+			bd.Produk[indeks[k]].DPP = dpp
+		}
+	}
+
+	bd.Total = bd.Subtotal - bd.Diskon
+	if setting.Mode == domain.PajakModeEksklusif {
+		bd.Total += bd.PPN
+	}
+}
+
+// deductLine mengurangi stok (dan batch FEFO) untuk satu baris penjualan dalam satuan dasar.
+// Produk paket mengurangi stok setiap komponennya; komponen disimpan di baris penjualan
+// agar stok yang dikembalikan saat edit/hapus sesuai dengan komposisi saat transaksi.
+func (rp *mongoRepoPenjualan) deductLine(sc context.Context, produk *domain.Produk, line *domain.ProdukJual, ref domain.StockRef) error {
+	if !produk.IsKit() {
+		err := rp.RepoProduk.DecreaseProdukStock(sc, produk.IDProduk, line.JumlahDasar(), ref)
+		if err != nil {
+			return fmt.Errorf("gagal mengurangi stok produk %s: %w", produk.IDProduk, err)
+		}
+
+		line.Batch, err = rp.RepoBatch.ConsumeFEFO(sc, produk.IDProduk, line.JumlahDasar())
+		if err != nil {
+			return fmt.Errorf("gagal mengurangi batch produk %s: %w", produk.IDProduk, err)
+		}
+		return nil
+	}
+
+	line.Komponen = produk.Komponen
+	line.Batch = nil
+	hargaPokok := 0
+	for _, komponen := range produk.Komponen {
+		component, err := rp.RepoProduk.GetProdukById(sc, komponen.IDProduk)
+		if err != nil {
+			return fmt.Errorf("gagal mendapatkan komponen paket %s: %w", produk.NamaProduk, err)
+		}
+		hargaPokok += component.HargaPokok * komponen.Jumlah
+
+		jumlah := line.JumlahDasar() * komponen.Jumlah
+		err = rp.RepoProduk.DecreaseProdukStock(sc, komponen.IDProduk, jumlah, ref)
+		if err != nil {
+			return fmt.Errorf("gagal mengurangi stok komponen %s paket %s: %w", komponen.IDProduk, produk.NamaProduk, err)
+		}
+
+		batches, err := rp.RepoBatch.ConsumeFEFO(sc, komponen.IDProduk, jumlah)
+		if err != nil {
+			return fmt.Errorf("gagal mengurangi batch komponen %s: %w", komponen.IDProduk, err)
+		}
+		line.Batch = append(line.Batch, batches...)
+	}
+
+	// HPP paket adalah jumlah HPP komponennya
+	line.HargaPokok = hargaPokok * line.Konversi
+	return nil
+}
+
+// restoreLine mengembalikan stok dan batch dari baris penjualan yang diedit atau dihapus
+func (rp *mongoRepoPenjualan) restoreLine(sc context.Context, item domain.ProdukJual, ref domain.StockRef) error {
+	if len(item.Komponen) == 0 {
+		err := rp.RepoProduk.IncreaseProdukStock(sc, item.IDProduk, item.JumlahDasar(), ref)
+		if err != nil {
+			return fmt.Errorf("gagal mengembalikan stok produk %s: %v", item.IDProduk, err)
+		}
+	}
+
+	for _, komponen := range item.Komponen {
+		err := rp.RepoProduk.IncreaseProdukStock(sc, komponen.IDProduk, item.JumlahDasar()*komponen.Jumlah, ref)
+		if err != nil {
+			return fmt.Errorf("gagal mengembalikan stok komponen %s: %v", komponen.IDProduk, err)
+		}
+	}
+
+	return rp.RepoBatch.Restore(sc, item.Batch)
+}
+
+// stockUser menentukan user yang dicatat di kartu stok untuk sebuah penjualan
+func stockUser(ctx context.Context, bd *domain.Penjualan) string {
+	if user := domain.UserFromContext(ctx); user != "" {
+		return user
+	}
+	return bd.NamaPenjual
+}
@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"SIE-SRC/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriceLine(t *testing.T) {
+	produk := &domain.Produk{
+		NamaProduk: "Air Mineral",
+		Harga:      3000,
+		HargaPokok: 2000,
+		Satuan: []domain.ProdukSatuan{
+			{Nama: "dus", Konversi: 24, Harga: 60000},
+		},
+		HargaTier: []domain.HargaTier{
+			{MinJumlah: 12, Harga: 2800},
+			{MinJumlah: 48, Harga: 2400},
+			{Grup: "member", MinJumlah: 1, Harga: 2900},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		line         domain.ProdukJual
+		grup         string
+		wantSatuan   string
+		wantHarga    int
+		wantSubtotal int
+		wantHPP      int
+		wantTier     bool
+	}{
+		{name: "harga dasar tanpa tier", line: domain.ProdukJual{JumlahProduk: 2}, wantSatuan: "pcs", wantHarga: 3000, wantSubtotal: 6000, wantHPP: 2000},
+		{name: "tier jumlah", line: domain.ProdukJual{JumlahProduk: 12}, wantSatuan: "pcs", wantHarga: 2800, wantSubtotal: 33600, wantHPP: 2000, wantTier: true},
+		{name: "tier grup pelanggan", line: domain.ProdukJual{JumlahProduk: 1}, grup: "Member", wantSatuan: "pcs", wantHarga: 2900, wantSubtotal: 2900, wantHPP: 2000, wantTier: true},
+		// 1 dus = 24 pcs, tier 2800 x 24 = 67200 lebih mahal dari harga dus 60000
+		{name: "tier tidak dipakai jika lebih mahal dari harga satuan", line: domain.ProdukJual{JumlahProduk: 1, Satuan: "Dus"}, wantSatuan: "dus", wantHarga: 60000, wantSubtotal: 60000, wantHPP: 48000},
+		// 2 dus = 48 pcs, tier 2400 x 24 = 57600 lebih murah dari 60000
+		{name: "tier dihitung dari jumlah satuan dasar", line: domain.ProdukJual{JumlahProduk: 2, Satuan: "dus"}, wantSatuan: "dus", wantHarga: 57600, wantSubtotal: 115200, wantHPP: 48000, wantTier: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line := tt.line
+			err := priceLine(produk, &line, domain.NormalizeGrupPelanggan(tt.grup))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantSatuan, line.Satuan)
+			assert.Equal(t, tt.wantHarga, line.Harga)
+			assert.Equal(t, tt.wantSubtotal, line.Subtotal)
+			assert.Equal(t, tt.wantHPP, line.HargaPokok)
+			assert.Equal(t, tt.wantTier, line.Tier != nil)
+			assert.Equal(t, "Air Mineral", line.NamaProduk)
+		})
+	}
+}
+
+func TestPriceLineSatuanTidakTersedia(t *testing.T) {
+	produk := &domain.Produk{NamaProduk: "Air Mineral", Harga: 3000}
+
+	line := domain.ProdukJual{JumlahProduk: 1, Satuan: "karton"}
+	assert.Error(t, priceLine(produk, &line, ""))
+}
+
+func TestApplyPromos(t *testing.T) {
+	produkByID := map[string]*domain.Produk{
+		"P1": {IDProduk: "P1", Kategori: "Minuman"},
+		"P2": {IDProduk: "P2", Kategori: "Makanan"},
+		"P3": {IDProduk: "P3", Kategori: "Makanan"},
+	}
+	promos := []domain.Promo{
+		{IDPromo: "minuman", Aktif: true, Tipe: domain.PromoTipePersen, Nilai: 10, Cakupan: domain.PromoCakupanKategori, Target: []string{"minuman"}},
+		{IDPromo: "belanja", Aktif: true, Tipe: domain.PromoTipeNominal, Nilai: 1000, Cakupan: domain.PromoCakupanTransaksi, MinBelanja: 10000},
+		{IDPromo: "malam", Aktif: true, Tipe: domain.PromoTipePersen, Nilai: 50, Cakupan: domain.PromoCakupanTransaksi, JamMulai: "22:00", JamSelesai: "02:00"},
+	}
+	newPenjualan := func() *domain.Penjualan {
+		bd := &domain.Penjualan{Produk: []domain.ProdukJual{
+			{IDProduk: "P1", JumlahProduk: 2, Harga: 5000, Subtotal: 10000},
+			{IDProduk: "P2", JumlahProduk: 1, Harga: 3000, Subtotal: 3000},
+			{IDProduk: "P3", JumlahProduk: 1, Harga: 1000, Subtotal: 1000},
+		}}
+		// Tanggal dari klien diabaikan, promo dievaluasi pada waktu server
+		bd.Tanggal = time.Date(2026, time.October, 16, 23, 0, 0, 0, time.Local)
+		return bd
+	}
+
+	t.Run("diskon transaksi dibagi ke setiap baris", func(t *testing.T) {
+		bd := newPenjualan()
+		applyPromos(bd, produkByID, promos, time.Date(2026, time.October, 16, 12, 0, 0, 0, time.Local))
+
+		assert.Equal(t, 1000, bd.Produk[0].Diskon)
+		assert.Equal(t, 13000, bd.Subtotal)
+		assert.Equal(t, 1000, bd.Diskon)
+		assert.Equal(t, "belanja", bd.Promo.IDPromo)
+		assert.Equal(t, 12000, bd.Total)
+
+		// Nilai bersih 9000, 3000, 1000; sisa pembagian masuk ke baris terakhir
+		diskonTransaksi := 0
+		for _, line := range bd.Produk {
+			diskonTransaksi += line.DiskonTransaksi
+		}
+		assert.Equal(t, bd.Diskon, diskonTransaksi)
+		assert.Equal(t, []int{692, 230, 78}, []int{bd.Produk[0].DiskonTransaksi, bd.Produk[1].DiskonTransaksi, bd.Produk[2].DiskonTransaksi})
+	})
+
+	t.Run("happy hour dievaluasi pada waktu server", func(t *testing.T) {
+		bd := newPenjualan()
+		applyPromos(bd, produkByID, promos, time.Date(2026, time.October, 16, 23, 0, 0, 0, time.Local))
+
+		assert.Equal(t, "malam", bd.Promo.IDPromo)
+		assert.Equal(t, 6500, bd.Diskon)
+		assert.Equal(t, 6500, bd.Total)
+	})
+}
+
+func TestApplyPajakDPPBaris(t *testing.T) {
+	tests := []struct {
+		name      string
+		mode      string
+		produk    []domain.ProdukJual
+		diskon    int
+		wantDPP   []int
+		wantTotal int
+	}{
+		{
+			name: "inklusif dibagi per tarif",
+			mode: domain.PajakModeInklusif,
+			produk: []domain.ProdukJual{
+				{Subtotal: 55500, TarifPajak: 11},
+				{Subtotal: 5000, TarifPajak: 0},
+				{Subtotal: 60000, Diskon: 4500, TarifPajak: 11},
+			},
+			wantDPP:   []int{50000, 5000, 50000},
+			wantTotal: 116000,
+		},
+		{
+			name: "eksklusif setelah diskon transaksi",
+			mode: domain.PajakModeEksklusif,
+			produk: []domain.ProdukJual{
+				{Subtotal: 30000, DiskonTransaksi: 300, TarifPajak: 11},
+				{Subtotal: 70000, DiskonTransaksi: 700, TarifPajak: 11},
+			},
+			diskon:    1000,
+			wantDPP:   []int{29700, 69300},
+			wantTotal: 109890,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bd := &domain.Penjualan{Produk: tt.produk, Diskon: tt.diskon}
+			for _, line := range tt.produk {
+				bd.Subtotal += line.Subtotal - line.Diskon
+			}
+
+			applyPajak(bd, domain.PajakSetting{Mode: tt.mode, Pembulatan: domain.PembulatanNormal})
+
+			dpp := make([]int, len(bd.Produk))
+			for j, line := range bd.Produk {
+				dpp[j] = line.DPP
+			}
+			assert.Equal(t, tt.wantDPP, dpp)
+			assert.Equal(t, bd.DPP, sumInts(dpp))
+			assert.Equal(t, tt.wantTotal, bd.Total)
+		})
+	}
+}
+
+func sumInts(values []int) int {
+	total := 0
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
+
+func TestPromosForEdit(t *testing.T) {
+	all := []domain.Promo{
+		{IDPromo: "aktif", Aktif: true},
+		{IDPromo: "baris-nonaktif", Aktif: false},
+		{IDPromo: "transaksi-nonaktif", Aktif: false},
+		{IDPromo: "lain-nonaktif", Aktif: false},
+	}
+	existing := &domain.Penjualan{
+		Produk: []domain.ProdukJual{
+			{IDProduk: "P1", Promo: &domain.PromoDipakai{IDPromo: "baris-nonaktif"}},
+			{IDProduk: "P2"},
+		},
+		Promo: &domain.PromoDipakai{IDPromo: "transaksi-nonaktif"},
+	}
+
+	promos := promosForEdit(all, existing)
+
+	ids := make([]string, 0, len(promos))
+	for _, promo := range promos {
+		assert.True(t, promo.Aktif)
+		ids = append(ids, promo.IDPromo)
+	}
+	assert.Equal(t, []string{"aktif", "baris-nonaktif", "transaksi-nonaktif"}, ids)
+	assert.False(t, all[1].Aktif, "promo asli tidak boleh ikut berubah")
+}
+
+func TestApplyPromosEditSetelahPromoBerakhir(t *testing.T) {
+	selesai := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.Local)
+	promos := []domain.Promo{
+		{IDPromo: "september", Aktif: true, Tipe: domain.PromoTipePersen, Nilai: 10, Cakupan: domain.PromoCakupanTransaksi, SelesaiTanggal: &selesai},
+	}
+	bd := &domain.Penjualan{Produk: []domain.ProdukJual{{IDProduk: "P1", JumlahProduk: 1, Harga: 10000, Subtotal: 10000}}}
+
+	// Dievaluasi pada waktu transaksi asli, diskon tetap ada walaupun promo sudah berakhir
+	applyPromos(bd, nil, promos, time.Date(2026, time.September, 20, 10, 0, 0, 0, time.Local))
+	assert.Equal(t, 1000, bd.Diskon)
+
+	applyPromos(bd, nil, promos, time.Date(2026, time.October, 5, 10, 0, 0, 0, time.Local))
+	assert.Equal(t, 0, bd.Diskon)
+}
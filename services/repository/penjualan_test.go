@@ -133,10 +133,13 @@ func TestMongoRepoPenjualan_Update(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	_, err := repo.CreateBulk(ctx, []domain.Penjualan{produk})
+	created, err := repo.CreateBulk(ctx, []domain.Penjualan{produk})
 	assert.NoError(t, err)
 
 	produk.Total = 200000
+	if len(created) > 0 {
+		produk.Version = created[0].Version
+	}
 
 	err = repo.Update(ctx, &produk)
 	assert.NoError(t, err)
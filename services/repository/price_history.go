@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"SIE-SRC/domain"
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoRepoPriceHistory struct {
+	DB *mongo.Database
+}
+
+func NewMongoRepoPriceHistory(client *mongo.Database) domain.PriceHistoryRepository {
+	return &mongoRepoPriceHistory{
+		DB: client,
+	}
+}
+
+var _PriceHistory = "price_history"
+
+// Record mencatat satu atau beberapa perubahan harga jual
+func (rp *mongoRepoPriceHistory) Record(ctx context.Context, history ...domain.PriceHistory) error {
+	DataHistory := rp.DB.Collection(_PriceHistory)
+
+	if len(history) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	docs := make([]interface{}, 0, len(history))
+	for _, item := range history {
+		if item.Timestamp.IsZero() {
+			item.Timestamp = now
+		}
+		docs = append(docs, item)
+	}
+
+	_, err := DataHistory.InsertMany(ctx, docs)
+	if err != nil {
+		return fmt.Errorf("gagal mencatat riwayat harga: %w", err)
+	}
+
+	return nil
+}
+
+// GetByProduk menampilkan riwayat harga sebuah produk, urut dari yang paling lama
+func (rp *mongoRepoPriceHistory) GetByProduk(ctx context.Context, idProduk string) ([]domain.PriceHistory, error) {
+	DataHistory := rp.DB.Collection(_PriceHistory)
+
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := DataHistory.Find(ctx, bson.M{"id_produk": idProduk}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil riwayat harga: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	history := make([]domain.PriceHistory, 0)
+	if err := cursor.All(ctx, &history); err != nil {
+		return nil, fmt.Errorf("gagal membaca riwayat harga: %v", err)
+	}
+
+	return history, nil
+}
@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"SIE-SRC/domain"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoRepoPriceSchedule struct {
+	DB         *mongo.Database
+	RepoProduk domain.ProdukRepository
+}
+
+func NewMongoRepoPriceSchedule(client *mongo.Database, produkRepo domain.ProdukRepository) domain.PriceScheduleRepository {
+	return &mongoRepoPriceSchedule{
+		DB:         client,
+		RepoProduk: produkRepo,
+	}
+}
+
+var _PriceSchedule = "price_schedule"
+
+// errScheduleClaimed menandai jadwal yang sudah diterapkan atau dibatalkan oleh proses lain
+var errScheduleClaimed = errors.New("jadwal harga sudah diproses")
+
+// Create menjadwalkan perubahan harga jual untuk waktu yang akan datang
+func (rp *mongoRepoPriceSchedule) Create(ctx context.Context, bd *domain.PriceSchedule) (domain.PriceSchedule, error) {
+	DataSchedule := rp.DB.Collection(_PriceSchedule)
+
+	if bd.IDProduk == "" {
+		return domain.PriceSchedule{}, fmt.Errorf("id produk tidak boleh kosong")
+	}
+	if bd.HargaBaru <= 0 {
+		return domain.PriceSchedule{}, fmt.Errorf("harga baru harus lebih dari 0")
+	}
+	if !bd.BerlakuMulai.After(time.Now()) {
+		return domain.PriceSchedule{}, fmt.Errorf("waktu berlaku harus di masa depan")
+	}
+
+	if _, err := rp.RepoProduk.GetProdukById(ctx, bd.IDProduk); err != nil {
+		return domain.PriceSchedule{}, err
+	}
+
+	bd.ID = primitive.NewObjectID()
+	bd.Status = domain.PriceScheduleStatusPending
+	bd.DibuatOleh = domain.UserFromContext(ctx)
+	bd.CreatedAt = time.Now()
+	bd.AppliedAt = nil
+
+	if _, err := DataSchedule.InsertOne(ctx, bd); err != nil {
+		return domain.PriceSchedule{}, fmt.Errorf("gagal menyimpan jadwal harga: %v", err)
+	}
+
+	return *bd, nil
+}
+
+func (rp *mongoRepoPriceSchedule) find(ctx context.Context, filter bson.M) ([]domain.PriceSchedule, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "berlaku_mulai", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := rp.DB.Collection(_PriceSchedule).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil jadwal harga: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	schedules := make([]domain.PriceSchedule, 0)
+	if err := cursor.All(ctx, &schedules); err != nil {
+		return nil, fmt.Errorf("gagal membaca jadwal harga: %v", err)
+	}
+
+	return schedules, nil
+}
+
+// GetPendingByProduk menampilkan jadwal harga sebuah produk yang belum diterapkan
+func (rp *mongoRepoPriceSchedule) GetPendingByProduk(ctx context.Context, idProduk string) ([]domain.PriceSchedule, error) {
+	return rp.find(ctx, bson.M{"id_produk": idProduk, "status": domain.PriceScheduleStatusPending})
+}
+
+// GetAllPending menampilkan semua jadwal harga yang belum diterapkan
+func (rp *mongoRepoPriceSchedule) GetAllPending(ctx context.Context) ([]domain.PriceSchedule, error) {
+	return rp.find(ctx, bson.M{"status": domain.PriceScheduleStatusPending})
+}
+
+// Cancel membatalkan jadwal yang belum diterapkan
+func (rp *mongoRepoPriceSchedule) Cancel(ctx context.Context, id string) error {
+	DataSchedule := rp.DB.Collection(_PriceSchedule)
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("id jadwal %s tidak valid", id)
+	}
+
+	result, err := DataSchedule.UpdateOne(ctx,
+		bson.M{"_id": objectID, "status": domain.PriceScheduleStatusPending},
+		bson.M{"$set": bson.M{"status": domain.PriceScheduleStatusCancelled}},
+	)
+	if err != nil {
+		return fmt.Errorf("gagal membatalkan jadwal harga: %v", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("jadwal harga %s tidak ditemukan atau sudah tidak aktif", id)
+	}
+
+	return nil
+}
+
+// ApplyDue menerapkan semua jadwal yang sudah jatuh tempo, urut dari yang paling awal.
+// Setiap jadwal diterapkan dalam transaksinya sendiri; jadwal yang gagal tetap pending
+// dan dicoba lagi pada putaran berikutnya, kecuali produknya sudah dihapus (status failed).
+func (rp *mongoRepoPriceSchedule) ApplyDue(ctx context.Context, now time.Time) (int, error) {
+	due, err := rp.find(ctx, bson.M{
+		"status":        domain.PriceScheduleStatusPending,
+		"berlaku_mulai": bson.M{"$lte": now},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	for _, schedule := range due {
+		// Produk yang sudah dihapus tidak akan pernah bisa diterapkan; tandai gagal agar tidak diulang terus
+		active, err := rp.DB.Collection(_Produk).CountDocuments(ctx, bson.M{"_id": schedule.IDProduk, "is_deleted": nil})
+		if err != nil {
+			log.Printf("gagal memeriksa produk jadwal harga %s: %v", schedule.ID.Hex(), err)
+			continue
+		}
+		if active == 0 {
+			if err := rp.markFailed(ctx, schedule, "produk sudah dihapus"); err != nil {
+				log.Printf("gagal menandai jadwal harga %s: %v", schedule.ID.Hex(), err)
+			}
+			continue
+		}
+
+		err = rp.apply(ctx, schedule)
+		if errors.Is(err, errScheduleClaimed) {
+			continue
+		}
+		if err != nil {
+			log.Printf("gagal menerapkan jadwal harga %s: %v", schedule.ID.Hex(), err)
+			continue
+		}
+		applied++
+	}
+
+	return applied, nil
+}
+
+// markFailed menandai jadwal pending yang tidak bisa diterapkan beserta alasannya
+func (rp *mongoRepoPriceSchedule) markFailed(ctx context.Context, schedule domain.PriceSchedule, alasan string) error {
+	_, err := rp.DB.Collection(_PriceSchedule).UpdateOne(ctx,
+		bson.M{"_id": schedule.ID, "status": domain.PriceScheduleStatusPending},
+		bson.M{"$set": bson.M{"status": domain.PriceScheduleStatusFailed, "keterangan": alasan}},
+	)
+	if err != nil {
+		return fmt.Errorf("gagal memperbarui jadwal harga: %v", err)
+	}
+	return nil
+}
+
+func (rp *mongoRepoPriceSchedule) apply(ctx context.Context, schedule domain.PriceSchedule) error {
+	DataSchedule := rp.DB.Collection(_PriceSchedule)
+
+	sesi, err := rp.DB.Client().StartSession()
+	if err != nil {
+		return fmt.Errorf("gagal memulai sesi: %v", err)
+	}
+	defer sesi.EndSession(ctx)
+
+	err = mongo.WithSession(ctx, sesi, func(sc mongo.SessionContext) error {
+		if err := sesi.StartTransaction(); err != nil {
+			return fmt.Errorf("gagal memulai transaksi: %v", err)
+		}
+
+		// Klaim jadwal lebih dulu agar tidak diterapkan dua kali oleh instance lain
+		now := time.Now()
+		result, err := DataSchedule.UpdateOne(sc,
+			bson.M{"_id": schedule.ID, "status": domain.PriceScheduleStatusPending},
+			bson.M{"$set": bson.M{"status": domain.PriceScheduleStatusApplied, "applied_at": now}},
+		)
+		if err != nil {
+			return fmt.Errorf("gagal memperbarui jadwal harga: %v", err)
+		}
+		if result.MatchedCount == 0 {
+			// Sudah diterapkan atau dibatalkan sejak dibaca, tidak dihitung sebagai diterapkan
+			return errScheduleClaimed
+		}
+
+		err = rp.RepoProduk.SetHarga(sc, schedule.IDProduk, schedule.HargaBaru, domain.PriceRef{
+			Source: domain.PriceSourceSchedule,
+			RefID:  schedule.ID.Hex(),
+			User:   schedule.DibuatOleh,
+		})
+		if err != nil {
+			return err
+		}
+
+		return sesi.CommitTransaction(sc)
+	})
+
+	if err != nil {
+		abortErr := sesi.AbortTransaction(ctx)
+		if abortErr != nil {
+			log.Printf("Error saat abort transaksi: %v", abortErr)
+		}
+		return err
+	}
+
+	return nil
+}
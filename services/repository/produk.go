@@ -1,62 +1,98 @@
 package repository
 
 import (
+	"SIE-SRC/config"
 	"SIE-SRC/domain"
+	"SIE-SRC/services/notification"
 	"context"
 	"fmt"
 	"log"
-	"strconv"
+	"sort"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type mongoRepoProduk struct {
-	DB *mongo.Database
+	DB       *mongo.Database
+	Counter  domain.CounterRepository
+	Movement domain.StockMovementRepository
+	Price    domain.PriceHistoryRepository
+	Batch    domain.StockBatchRepository
+	Notifier domain.Notifier
+	idFormat domain.CounterFormat
+	idSeeder *counterSeeder
+	// barcodePrefix adalah awalan EAN-13 internal untuk produk tanpa barcode
+	barcodePrefix string
 }
 
 func NewMongoRepoProduk(client *mongo.Database) domain.ProdukRepository {
 	return &mongoRepoProduk{
-		DB: client,
+		DB:       client,
+		Counter:  NewMongoRepoCounter(client),
+		Movement: NewMongoRepoStockMovement(client),
+		Price:    NewMongoRepoPriceHistory(client),
+		Batch:    NewMongoRepoStockBatch(client),
+		Notifier: notification.NewSlackNotifier(),
+		idFormat: domain.CounterFormat{
+			Prefix: config.GetProdukIDPrefix(),
+			Width:  config.GetProdukIDWidth(),
+		},
+		idSeeder:      &counterSeeder{},
+		barcodePrefix: config.GetBarcodePrefix(),
 	}
 }
 
 var _Produk = "produk"
 
-// GenerateNextID generates the next available ID
-func (rp *mongoRepoProduk) GenerateNextID(ctx context.Context) (string, error) {
-	DataProduk := rp.DB.Collection(_Produk)
-
-	// Find the last document sorted by _id in descending order
-	opts := options.FindOne().SetSort(bson.M{"_id": -1})
-	var lastProduct domain.Produk
+// _BarcodeCounter adalah nama counter untuk nomor EAN-13 internal
+var _BarcodeCounter = "barcode_ean13"
 
-	err := DataProduk.FindOne(ctx, bson.M{}, opts).Decode(&lastProduct)
+// reserveIDs memesan n ID produk berurutan dari counters collection
+func (rp *mongoRepoProduk) reserveIDs(ctx context.Context, n int) ([]string, error) {
+	err := rp.idSeeder.ensure(ctx, func(ctx context.Context) error {
+		return seedFromCollection(ctx, rp.Counter, rp.DB.Collection(_Produk), "_id", _Produk, rp.idFormat)
+	})
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			// If no documents exist, start with "001"
-			return "001", nil
-		}
-		return "", fmt.Errorf("error finding last product: %v", err)
+		return nil, err
 	}
 
-	// Parse the last ID and increment
-	lastID, err := strconv.Atoi(lastProduct.IDProduk)
+	last, err := rp.Counter.NextSequence(ctx, _Produk, int64(n))
 	if err != nil {
-		return "", fmt.Errorf("error parsing last ID: %v", err)
+		return nil, err
 	}
 
-	// Format new ID with leading zeros
-	newID := fmt.Sprintf("%03d", lastID+1)
-	return newID, nil
+	ids := make([]string, 0, n)
+	for seq := last - int64(n) + 1; seq <= last; seq++ {
+		ids = append(ids, formatSequenceID(rp.idFormat, seq))
+	}
+	return ids, nil
+}
+
+// GenerateNextID generates the next available ID
+func (rp *mongoRepoProduk) GenerateNextID(ctx context.Context) (string, error) {
+	ids, err := rp.reserveIDs(ctx, 1)
+	if err != nil {
+		return "", fmt.Errorf("error generating next ID: %v", err)
+	}
+	return ids[0], nil
 }
 
 // Membuat Data Produk
 func (rp *mongoRepoProduk) CreateProduk(ctx context.Context, bd *domain.Produk) (domain.Produk, error) {
 	DataProduk := rp.DB.Collection(_Produk)
 
+	if err := rp.validateProduk(ctx, bd, nil); err != nil {
+		return domain.Produk{}, err
+	}
+
+	// Gambar hanya bisa ditambahkan lewat unggahan
+	bd.Gambar = nil
+
 	if bd.IDProduk == "" {
 		// Generate ID if not provided
 		nextID, err := rp.GenerateNextID(ctx)
@@ -68,11 +104,48 @@ func (rp *mongoRepoProduk) CreateProduk(ctx context.Context, bd *domain.Produk)
 
 	// Set current time for UpdatedAt
 	bd.UpdatedAt = time.Now()
+	bd.Version = 1
 
-	// Insert document
-	_, err := DataProduk.InsertOne(ctx, bd)
+	sesi, err := rp.DB.Client().StartSession()
 	if err != nil {
-		return domain.Produk{}, fmt.Errorf("error inserting product: %v", err)
+		return domain.Produk{}, fmt.Errorf("gagal memulai sesi: %v", err)
+	}
+	defer sesi.EndSession(ctx)
+
+	// Produk, riwayat harga, dan stok awal disimpan dalam satu transaksi agar kartu stok selalu sesuai
+	_, err = sesi.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		if _, err := DataProduk.InsertOne(sc, bd); err != nil {
+			return nil, fmt.Errorf("error inserting product: %w", err)
+		}
+
+		err := rp.Price.Record(sc, domain.PriceHistory{
+			IDProduk:  bd.IDProduk,
+			HargaBaru: bd.Harga,
+			Source:    domain.PriceSourceCreate,
+			User:      domain.UserFromContext(ctx),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		// Catat stok awal ke kartu stok
+		if bd.Stok != 0 {
+			err = rp.Movement.Record(sc, domain.StockMovement{
+				IDProduk: bd.IDProduk,
+				Delta:    bd.Stok,
+				Saldo:    bd.Stok,
+				Reason:   domain.StockReasonManualAdjustment,
+				RefID:    bd.IDProduk,
+				User:     domain.UserFromContext(ctx),
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return domain.Produk{}, err
 	}
 
 	return *bd, nil
@@ -97,9 +170,284 @@ func (rp *mongoRepoProduk) GetAllProduk(ctx context.Context) ([]domain.Produk, e
 		return nil, err
 	}
 
+	if err := rp.fillKitStock(ctx, products); err != nil {
+		return nil, err
+	}
+
+	return products, nil
+}
+
+// FindProduk menampilkan produk aktif yang sesuai filter
+func (rp *mongoRepoProduk) FindProduk(ctx context.Context, filter domain.ProdukFilter) ([]domain.Produk, error) {
+	DataProduk := rp.DB.Collection(_Produk)
+
+	query := bson.M{
+		"is_deleted": nil,
+	}
+	if filter.IDSupplier != "" {
+		query["id_supplier"] = filter.IDSupplier
+	}
+	if filter.IDInduk != "" {
+		query["id_induk"] = filter.IDInduk
+	}
+	if filter.LowStock {
+		query["reorder_point"] = bson.M{"$gt": 0}
+		query["$expr"] = bson.M{"$lte": bson.A{"$stok_barang", "$reorder_point"}}
+	}
+
+	cursor, err := DataProduk.Find(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	products := make([]domain.Produk, 0)
+	if err = cursor.All(ctx, &products); err != nil {
+		return nil, err
+	}
+
+	if err := rp.fillKitStock(ctx, products); err != nil {
+		return nil, err
+	}
+
 	return products, nil
 }
 
+// validateSupplier memastikan supplier utama produk terdaftar dan belum dihapus
+func (rp *mongoRepoProduk) validateSupplier(ctx context.Context, idSupplier string) error {
+	if idSupplier == "" {
+		return nil
+	}
+
+	count, err := rp.DB.Collection(_Supplier).CountDocuments(ctx, bson.M{"_id": idSupplier, "is_deleted": nil})
+	if err != nil {
+		return fmt.Errorf("gagal memeriksa supplier: %v", err)
+	}
+	if count == 0 {
+		return fmt.Errorf("supplier dengan ID %s tidak ditemukan", idSupplier)
+	}
+	return nil
+}
+
+// validateInduk memeriksa varian: induk harus ada, atribut varian harus lengkap sesuai
+// dimensi induk, dan kombinasinya tidak boleh sama dengan varian lain.
+// Kategori yang kosong diwarisi dari induk.
+func (rp *mongoRepoProduk) validateInduk(ctx context.Context, bd *domain.Produk) error {
+	if bd.IDInduk == "" {
+		bd.Atribut = nil
+		return nil
+	}
+
+	var induk domain.ProdukInduk
+	err := rp.DB.Collection(_ProdukInduk).FindOne(ctx, bson.M{"_id": bd.IDInduk}).Decode(&induk)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return fmt.Errorf("produk induk dengan ID %s tidak ditemukan", bd.IDInduk)
+		}
+		return fmt.Errorf("gagal mendapatkan produk induk: %v", err)
+	}
+
+	atribut := make(map[string]string, len(induk.Atribut))
+	for key, value := range bd.Atribut {
+		atribut[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+	}
+	for _, nama := range induk.Atribut {
+		if atribut[nama] == "" {
+			return fmt.Errorf("atribut %s wajib diisi untuk varian %s", nama, induk.NamaProduk)
+		}
+	}
+	if len(atribut) != len(induk.Atribut) {
+		return fmt.Errorf("atribut varian harus salah satu dari: %s", strings.Join(induk.Atribut, ", "))
+	}
+	bd.Atribut = atribut
+
+	cursor, err := rp.DB.Collection(_Produk).Find(ctx, bson.M{
+		"id_induk":   bd.IDInduk,
+		"is_deleted": nil,
+		"_id":        bson.M{"$ne": bd.IDProduk},
+	})
+	if err != nil {
+		return fmt.Errorf("gagal memeriksa varian: %v", err)
+	}
+	var siblings []domain.Produk
+	if err := cursor.All(ctx, &siblings); err != nil {
+		return fmt.Errorf("gagal membaca varian: %v", err)
+	}
+	for _, sibling := range siblings {
+		same := true
+		for _, nama := range induk.Atribut {
+			if !strings.EqualFold(sibling.Atribut[nama], atribut[nama]) {
+				same = false
+				break
+			}
+		}
+		if same {
+			return fmt.Errorf("varian dengan atribut yang sama sudah ada: %s", sibling.NamaProduk)
+		}
+	}
+
+	if bd.Kategori == "" && bd.SubKategori == "" {
+		bd.Kategori = induk.Kategori
+		bd.SubKategori = induk.SubKategori
+	}
+	return nil
+}
+
+// validateKomponen memeriksa komponen produk paket. Paket tidak boleh bersarang
+// dan tidak menyimpan stok sendiri karena stoknya dihitung dari komponen.
+func (rp *mongoRepoProduk) validateKomponen(ctx context.Context, bd *domain.Produk) error {
+	if !bd.IsKit() {
+		return nil
+	}
+
+	ids := make([]string, 0, len(bd.Komponen))
+	seen := make(map[string]bool, len(bd.Komponen))
+	for i, komponen := range bd.Komponen {
+		if komponen.IDProduk == "" {
+			return fmt.Errorf("id produk komponen ke-%d tidak boleh kosong", i+1)
+		}
+		if komponen.IDProduk == bd.IDProduk {
+			return fmt.Errorf("produk paket tidak boleh menjadi komponen dirinya sendiri")
+		}
+		if seen[komponen.IDProduk] {
+			return fmt.Errorf("komponen %s muncul lebih dari sekali", komponen.IDProduk)
+		}
+		if komponen.Jumlah <= 0 {
+			return fmt.Errorf("jumlah komponen %s harus lebih dari 0", komponen.IDProduk)
+		}
+		seen[komponen.IDProduk] = true
+		ids = append(ids, komponen.IDProduk)
+	}
+
+	cursor, err := rp.DB.Collection(_Produk).Find(ctx, bson.M{"_id": bson.M{"$in": ids}, "is_deleted": nil})
+	if err != nil {
+		return fmt.Errorf("gagal memeriksa komponen: %v", err)
+	}
+	var components []domain.Produk
+	if err := cursor.All(ctx, &components); err != nil {
+		return fmt.Errorf("gagal membaca komponen: %v", err)
+	}
+
+	found := make(map[string]domain.Produk, len(components))
+	for _, component := range components {
+		found[component.IDProduk] = component
+	}
+	for i, komponen := range bd.Komponen {
+		component, ok := found[komponen.IDProduk]
+		if !ok {
+			return fmt.Errorf("komponen dengan ID %s tidak ditemukan", komponen.IDProduk)
+		}
+		if component.IsKit() {
+			return fmt.Errorf("produk paket %s tidak dapat menjadi komponen paket lain", component.NamaProduk)
+		}
+		bd.Komponen[i].NamaProduk = component.NamaProduk
+	}
+
+	bd.Stok = 0
+	return nil
+}
+
+// fillKitStock menghitung stok produk paket dari stok komponennya,
+// yaitu jumlah paket utuh yang masih bisa dibentuk dari komponen yang paling terbatas.
+func (rp *mongoRepoProduk) fillKitStock(ctx context.Context, products []domain.Produk) error {
+	ids := make([]string, 0)
+	for _, product := range products {
+		for _, komponen := range product.Komponen {
+			ids = append(ids, komponen.IDProduk)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	cursor, err := rp.DB.Collection(_Produk).Find(ctx, bson.M{"_id": bson.M{"$in": ids}, "is_deleted": nil})
+	if err != nil {
+		return fmt.Errorf("gagal mengambil stok komponen: %v", err)
+	}
+	var components []domain.Produk
+	if err := cursor.All(ctx, &components); err != nil {
+		return fmt.Errorf("gagal membaca stok komponen: %v", err)
+	}
+
+	stok := make(map[string]int, len(components))
+	for _, component := range components {
+		stok[component.IDProduk] = component.Stok
+	}
+
+	for i := range products {
+		if products[i].IsKit() {
+			products[i].Stok = products[i].KitStock(stok)
+		}
+	}
+
+	return nil
+}
+
+// validateProduk menjalankan validasi yang sama untuk create, update, dan import produk.
+// index boleh nil; import mengirim index yang sudah dimuat agar tidak dibaca ulang per baris.
+func (rp *mongoRepoProduk) validateProduk(ctx context.Context, bd *domain.Produk, index *kategoriIndex) error {
+	if err := rp.validateSupplier(ctx, bd.IDSupplier); err != nil {
+		return err
+	}
+	if err := validateReorder(bd); err != nil {
+		return err
+	}
+	if err := domain.ValidateTarifPajak(bd.TarifPajak); err != nil {
+		return err
+	}
+	if err := normalizeSatuan(bd); err != nil {
+		return err
+	}
+	if err := rp.validateInduk(ctx, bd); err != nil {
+		return err
+	}
+
+	// Kategori dicocokkan setelah induk karena varian dapat mewarisi kategori induknya
+	var err error
+	if index == nil {
+		if index, err = loadKategoriIndex(ctx, rp.DB); err != nil {
+			return err
+		}
+	}
+	bd.Kategori, bd.SubKategori, err = index.resolve(bd.Kategori, bd.SubKategori)
+	if err != nil {
+		return err
+	}
+
+	return rp.validateKomponen(ctx, bd)
+}
+
+// normalizeSatuan mengisi satuan dasar default dan memvalidasi satuan tambahan serta tier harga produk
+func normalizeSatuan(bd *domain.Produk) error {
+	bd.SatuanDasar = bd.NamaSatuanDasar()
+	for i := range bd.Satuan {
+		bd.Satuan[i].Nama = strings.TrimSpace(bd.Satuan[i].Nama)
+	}
+	if err := bd.ValidateSatuan(); err != nil {
+		return err
+	}
+
+	// Tier disimpan urut per grup lalu jumlah minimum agar mudah dibaca
+	for i := range bd.HargaTier {
+		bd.HargaTier[i].Grup = domain.NormalizeGrupPelanggan(bd.HargaTier[i].Grup)
+	}
+	sort.SliceStable(bd.HargaTier, func(i, j int) bool {
+		if bd.HargaTier[i].Grup != bd.HargaTier[j].Grup {
+			return bd.HargaTier[i].Grup < bd.HargaTier[j].Grup
+		}
+		return bd.HargaTier[i].MinJumlah < bd.HargaTier[j].MinJumlah
+	})
+	return bd.ValidateHargaTier()
+}
+
+// validateReorder memastikan reorder point dan jumlah pemesanan tidak negatif
+func validateReorder(bd *domain.Produk) error {
+	if bd.ReorderPoint < 0 || bd.ReorderQty < 0 {
+		return fmt.Errorf("reorder point dan reorder qty tidak boleh negatif")
+	}
+	return nil
+}
+
 // Mencari Data Produk Berdasarkan ID Produk
 func (rp *mongoRepoProduk) GetProdukById(ctx context.Context, id string) (*domain.Produk, error) {
 	DataProduk := rp.DB.Collection(_Produk)
@@ -110,10 +458,15 @@ func (rp *mongoRepoProduk) GetProdukById(ctx context.Context, id string) (*domai
 		if err == mongo.ErrNoDocuments {
 			return nil, fmt.Errorf("produk dengan ID %s tidak ditemukan", id)
 		}
-		return nil, fmt.Errorf("gagal untuk mendapatkan produk: %v", err)
+		return nil, fmt.Errorf("gagal untuk mendapatkan produk: %w", err)
 	}
 
-	return &product, nil
+	products := []domain.Produk{product}
+	if err := rp.fillKitStock(ctx, products); err != nil {
+		return nil, err
+	}
+
+	return &products[0], nil
 }
 
 // Mencari Data Produk Berdasarkan Nama Produk
@@ -126,19 +479,33 @@ func (rp *mongoRepoProduk) GetProdukByName(ctx context.Context, nama string) (*d
 		if err == mongo.ErrNoDocuments {
 			return nil, fmt.Errorf("produk dengan Nama %s tidak ditemukan", nama)
 		}
-		return nil, fmt.Errorf("gagal untuk mendapatkan produk: %v", err)
+		return nil, fmt.Errorf("gagal untuk mendapatkan produk: %w", err)
+	}
+
+	products := []domain.Produk{product}
+	if err := rp.fillKitStock(ctx, products); err != nil {
+		return nil, err
 	}
 
-	return &product, nil
+	return &products[0], nil
 }
 
-// Memperbarui Data Produk
+// Memperbarui Data Produk.
+// Update hanya berhasil bila versi di database masih sama dengan bd.Version;
+// versi 0 hanya cocok dengan dokumen lama yang belum memiliki field version.
 func (rp *mongoRepoProduk) UpdateProduk(ctx context.Context, bd *domain.Produk) error {
 	DataProduk := rp.DB.Collection(_Produk)
 
+	if err := rp.validateProduk(ctx, bd, nil); err != nil {
+		return err
+	}
+
 	bd.UpdatedAt = time.Now()
 
-	filter := bson.M{"_id": bd.IDProduk}
+	filter := bson.M{"_id": bd.IDProduk, "is_deleted": nil, "version": bd.Version}
+	if bd.Version == 0 {
+		filter["version"] = bson.M{"$in": bson.A{0, nil}}
+	}
 	update := bson.M{
 		"$set": bson.M{
 			"nama_produk":    bd.NamaProduk,
@@ -147,20 +514,98 @@ func (rp *mongoRepoProduk) UpdateProduk(ctx context.Context, bd *domain.Produk)
 			"barcode_produk": bd.KodeProduk,
 			"harga":          bd.Harga,
 			"stok_barang":    bd.Stok,
+			"id_supplier":    bd.IDSupplier,
+			"satuan_dasar":   bd.SatuanDasar,
+			"satuan":         bd.Satuan,
+			"harga_tier":     bd.HargaTier,
+			"tarif_pajak":    bd.TarifPajak,
+			"id_induk":       bd.IDInduk,
+			"atribut":        bd.Atribut,
+			"komponen":       bd.Komponen,
+			"reorder_point":  bd.ReorderPoint,
+			"reorder_qty":    bd.ReorderQty,
 			"updated_at":     bd.UpdatedAt,
 		},
+		"$inc": bson.M{"version": 1},
+	}
+	// HPP normalnya dihitung dari penerimaan barang; nilai 0 berarti tidak diubah
+	if bd.HargaPokok > 0 {
+		update["$set"].(bson.M)["harga_pokok"] = bd.HargaPokok
 	}
 
-	result, err := DataProduk.UpdateOne(ctx, filter, update)
+	sesi, err := rp.DB.Client().StartSession()
 	if err != nil {
-		return fmt.Errorf("error updating product: %v", err)
+		return fmt.Errorf("gagal memulai sesi: %v", err)
 	}
+	defer sesi.EndSession(ctx)
 
-	if result.MatchedCount == 0 {
-		return fmt.Errorf("produk dengan ID %s tidak ditemukan", bd.IDProduk)
-	}
+	// Update produk, riwayat harga, dan kartu stok berjalan dalam satu transaksi
+	_, err = sesi.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		// Ambil dokumen sebelum update untuk menghitung selisih stok
+		opts := options.FindOneAndUpdate().SetReturnDocument(options.Before)
 
-	return nil
+		var previous domain.Produk
+		err := DataProduk.FindOneAndUpdate(sc, filter, update, opts).Decode(&previous)
+		if err != nil {
+			if err != mongo.ErrNoDocuments {
+				return nil, fmt.Errorf("error updating product: %w", err)
+			}
+
+			var current domain.Produk
+			err := DataProduk.FindOne(sc, bson.M{"_id": bd.IDProduk, "is_deleted": nil}).Decode(&current)
+			if err == mongo.ErrNoDocuments {
+				return nil, fmt.Errorf("produk dengan ID %s tidak ditemukan", bd.IDProduk)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("gagal untuk mendapatkan produk: %w", err)
+			}
+
+			return nil, &domain.VersionConflictError{
+				Expected: bd.Version,
+				Current:  &current,
+			}
+		}
+
+		if bd.Harga != previous.Harga {
+			err = rp.Price.Record(sc, domain.PriceHistory{
+				IDProduk:  bd.IDProduk,
+				HargaLama: previous.Harga,
+				HargaBaru: bd.Harga,
+				Source:    domain.PriceSourceManual,
+				User:      domain.UserFromContext(ctx),
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		// Perubahan stok lewat edit produk dicatat sebagai penyesuaian manual
+		if delta := bd.Stok - previous.Stok; delta != 0 {
+			err = rp.Movement.Record(sc, domain.StockMovement{
+				IDProduk: bd.IDProduk,
+				Delta:    delta,
+				Saldo:    bd.Stok,
+				Reason:   domain.StockReasonManualAdjustment,
+				RefID:    bd.IDProduk,
+				User:     domain.UserFromContext(ctx),
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			// Pengurangan manual diambil dari batch FEFO agar jumlah batch aktif tidak melebihi stok
+			if delta < 0 {
+				if _, err := rp.Batch.ConsumeFEFO(sc, bd.IDProduk, -delta); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		bd.Version = previous.Version + 1
+		return nil, nil
+	})
+
+	return err
 }
 
 // Menghapus Data Produk
@@ -173,6 +618,7 @@ func (rp *mongoRepoProduk) DeleteProduk(ctx context.Context, id string) error {
 		"$set": bson.M{
 			"is_deleted": now,
 		},
+		"$inc": bson.M{"version": 1},
 	}
 
 	result, err := DataProduk.UpdateOne(ctx, filter, update)
@@ -187,53 +633,258 @@ func (rp *mongoRepoProduk) DeleteProduk(ctx context.Context, id string) error {
 	return nil
 }
 
-// DecreaseProdukStock mengurangi stok produk
-func (rp *mongoRepoProduk) DecreaseProdukStock(ctx context.Context, id string, kuantitas int) error {
+// GetDeletedProduk menampilkan produk yang sudah dihapus (trash)
+func (rp *mongoRepoProduk) GetDeletedProduk(ctx context.Context) ([]domain.Produk, error) {
 	DataProduk := rp.DB.Collection(_Produk)
 
-	var product domain.Produk
-	err := DataProduk.FindOne(ctx, bson.M{"_id": id, "is_deleted": nil}).Decode(&product)
+	filter := bson.M{
+		"is_deleted": bson.M{"$ne": nil},
+	}
+	opts := options.Find().SetSort(bson.M{"is_deleted": -1})
+
+	cursor, err := DataProduk.Find(ctx, filter, opts)
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			return fmt.Errorf("produk dengan ID %s tidak ditemukan", id)
-		}
-		return fmt.Errorf("gagal untuk mendapatkan produk: %v", err)
+		return nil, err
 	}
+	defer cursor.Close(ctx)
+
+	products := make([]domain.Produk, 0)
+	if err = cursor.All(ctx, &products); err != nil {
+		return nil, err
+	}
+
+	return products, nil
+}
+
+// RestoreProduk mengembalikan satu atau beberapa produk dari trash
+func (rp *mongoRepoProduk) RestoreProduk(ctx context.Context, ids []string) (int64, error) {
+	DataProduk := rp.DB.Collection(_Produk)
 
-	if product.Stok < kuantitas {
-		return fmt.Errorf("stok tidak mencukupi, stok tersedia: %d, permintaan: %d", product.Stok, kuantitas)
+	if len(ids) == 0 {
+		return 0, fmt.Errorf("minimal harus ada satu ID produk")
 	}
 
+	filter := bson.M{
+		"_id":        bson.M{"$in": ids},
+		"is_deleted": bson.M{"$ne": nil},
+	}
 	update := bson.M{
-		"$inc": bson.M{"stok_barang": -kuantitas},
-		"$set": bson.M{"updated_at": time.Now()},
+		"$set": bson.M{
+			"is_deleted": nil,
+			"updated_at": time.Now(),
+		},
+		"$inc": bson.M{"version": 1},
 	}
 
-	result, err := DataProduk.UpdateOne(ctx, bson.M{"_id": id}, update)
+	result, err := DataProduk.UpdateMany(ctx, filter, update)
 	if err != nil {
-		return fmt.Errorf("gagal mengupdate stok: %v", err)
+		return 0, fmt.Errorf("gagal mengembalikan produk: %v", err)
 	}
 
 	if result.MatchedCount == 0 {
-		return fmt.Errorf("produk dengan ID %s tidak ditemukan", id)
+		return 0, fmt.Errorf("tidak ada produk terhapus dengan ID tersebut")
 	}
 
-	return nil
+	return result.ModifiedCount, nil
 }
 
-// IncreaseProdukStock menambah stok produk
-func (rp *mongoRepoProduk) IncreaseProdukStock(ctx context.Context, id string, kuantitas int) error {
+// PurgeDeletedProduk menghapus permanen produk yang dihapus sebelum tanggal tertentu.
+// Produk yang masih direferensikan oleh penjualan tidak ikut dihapus.
+func (rp *mongoRepoProduk) PurgeDeletedProduk(ctx context.Context, before time.Time) (domain.ProdukPurgeResult, error) {
 	DataProduk := rp.DB.Collection(_Produk)
+	ListPenjualan := rp.DB.Collection(_Penjualan)
+
+	result := domain.ProdukPurgeResult{Skipped: []string{}}
 
-	var existingProduct domain.Produk
-	err := DataProduk.FindOne(ctx, bson.M{"_id": id}).Decode(&existingProduct)
+	cursor, err := DataProduk.Find(ctx, bson.M{
+		"is_deleted": bson.M{"$ne": nil, "$lt": before},
+	})
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			return fmt.Errorf("produk dengan ID %s tidak ditemukan", id)
+		return result, fmt.Errorf("gagal mencari produk di trash: %v", err)
+	}
+	var candidates []domain.Produk
+	if err = cursor.All(ctx, &candidates); err != nil {
+		return result, fmt.Errorf("gagal membaca produk di trash: %v", err)
+	}
+
+	if len(candidates) == 0 {
+		return result, nil
+	}
+
+	ids := make([]string, 0, len(candidates))
+	for _, produk := range candidates {
+		ids = append(ids, produk.IDProduk)
+	}
+
+	// Cari produk yang masih dipakai di penjualan
+	referenced, err := ListPenjualan.Distinct(ctx, "produk.id_produk", bson.M{
+		"produk.id_produk": bson.M{"$in": ids},
+	})
+	if err != nil {
+		return result, fmt.Errorf("gagal memeriksa referensi penjualan: %v", err)
+	}
+
+	referencedIDs := make(map[string]bool)
+	for _, ref := range referenced {
+		if id, ok := ref.(string); ok {
+			referencedIDs[id] = true
+		}
+	}
+
+	purgeIDs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if referencedIDs[id] {
+			result.Skipped = append(result.Skipped, id)
+			continue
 		}
-		return fmt.Errorf("gagal mendapatkan data produk: %v", err)
+		purgeIDs = append(purgeIDs, id)
+	}
+
+	if len(purgeIDs) == 0 {
+		return result, nil
+	}
+
+	deleted, err := DataProduk.DeleteMany(ctx, bson.M{
+		"_id":        bson.M{"$in": purgeIDs},
+		"is_deleted": bson.M{"$ne": nil},
+	})
+	if err != nil {
+		return result, fmt.Errorf("gagal menghapus permanen produk: %v", err)
 	}
 
+	result.Purged = deleted.DeletedCount
+	log.Printf("Berhasil menghapus permanen %d produk, %d dilewati karena masih dipakai penjualan",
+		result.Purged, len(result.Skipped))
+	return result, nil
+}
+
+// DecreaseProdukStock mengurangi stok produk dengan satu update bersyarat (stok_barang >= kuantitas)
+// dan mencatat mutasinya ke kartu stok
+func (rp *mongoRepoProduk) DecreaseProdukStock(ctx context.Context, id string, kuantitas int, ref domain.StockRef) error {
+	DataProduk := rp.DB.Collection(_Produk)
+
+	if kuantitas <= 0 {
+		return fmt.Errorf("kuantitas harus lebih dari 0")
+	}
+
+	filter := bson.M{
+		"_id":         id,
+		"is_deleted":  nil,
+		"stok_barang": bson.M{"$gte": kuantitas},
+	}
+	update := bson.M{
+		"$inc": bson.M{"stok_barang": -kuantitas, "version": 1},
+		"$set": bson.M{"updated_at": time.Now()},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var updated domain.Produk
+	err := DataProduk.FindOneAndUpdate(ctx, filter, update, opts).Decode(&updated)
+	if err != nil {
+		if err != mongo.ErrNoDocuments {
+			return fmt.Errorf("gagal mengupdate stok: %w", err)
+		}
+
+		// Bedakan produk tidak ada dengan stok yang tidak cukup
+		var product domain.Produk
+		err := DataProduk.FindOne(ctx, bson.M{"_id": id, "is_deleted": nil}).Decode(&product)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				return fmt.Errorf("produk dengan ID %s tidak ditemukan", id)
+			}
+			return fmt.Errorf("gagal untuk mendapatkan produk: %w", err)
+		}
+
+		return &domain.InsufficientStockError{
+			IDProduk:   id,
+			NamaProduk: product.NamaProduk,
+			Tersedia:   product.Stok,
+			Diminta:    kuantitas,
+		}
+	}
+
+	err = rp.Movement.Record(ctx, domain.StockMovement{
+		IDProduk: id,
+		Delta:    -kuantitas,
+		Saldo:    updated.Stok,
+		Reason:   ref.Reason,
+		RefID:    ref.RefID,
+		User:     ref.User,
+	})
+	if err != nil {
+		return err
+	}
+
+	rp.notifyLowStock(ctx, updated, kuantitas, ref)
+	return nil
+}
+
+// notifyLowStock mengirim notifikasi hanya saat stok baru saja melewati reorder point,
+// sehingga penjualan berikutnya di bawah ambang tidak mengirim notifikasi berulang.
+// Di dalam transaksi (lihat withLowStockOutbox) notifikasi ditunda sampai commit.
+func (rp *mongoRepoProduk) notifyLowStock(ctx context.Context, updated domain.Produk, kuantitas int, ref domain.StockRef) {
+	if rp.Notifier == nil || updated.ReorderPoint <= 0 {
+		return
+	}
+	if updated.Stok > updated.ReorderPoint || updated.Stok+kuantitas <= updated.ReorderPoint {
+		return
+	}
+
+	event := domain.LowStockEvent{
+		IDProduk:     updated.IDProduk,
+		NamaProduk:   updated.NamaProduk,
+		Stok:         updated.Stok,
+		ReorderPoint: updated.ReorderPoint,
+		ReorderQty:   updated.ReorderQty,
+		Reason:       ref.Reason,
+		RefID:        ref.RefID,
+		Timestamp:    time.Now(),
+	}
+	send := func(ctx context.Context) {
+		if err := rp.Notifier.NotifyLowStock(ctx, event); err != nil {
+			log.Printf("gagal mengirim notifikasi stok rendah %s: %v", event.IDProduk, err)
+		}
+	}
+
+	if outbox, ok := ctx.Value(lowStockOutboxKey{}).(*lowStockOutbox); ok {
+		outbox.pending = append(outbox.pending, send)
+		return
+	}
+	send(ctx)
+}
+
+// lowStockOutbox menampung notifikasi stok rendah yang muncul di dalam transaksi.
+// Notifikasi baru dikirim lewat flush setelah commit, sehingga transaksi yang
+// dibatalkan tidak pernah mengirim notifikasi.
+type lowStockOutbox struct {
+	pending []func(ctx context.Context)
+}
+
+type lowStockOutboxKey struct{}
+
+// withLowStockOutbox menyiapkan outbox notifikasi stok rendah untuk sebuah transaksi
+func withLowStockOutbox(ctx context.Context) (context.Context, *lowStockOutbox) {
+	outbox := &lowStockOutbox{}
+	return context.WithValue(ctx, lowStockOutboxKey{}, outbox), outbox
+}
+
+// reset membuang notifikasi dari percobaan transaksi yang dibatalkan
+func (o *lowStockOutbox) reset() {
+	o.pending = nil
+}
+
+// flush mengirim notifikasi yang tertunda; dipanggil hanya setelah transaksi di-commit
+func (o *lowStockOutbox) flush(ctx context.Context) {
+	for _, send := range o.pending {
+		send(ctx)
+	}
+	o.pending = nil
+}
+
+// IncreaseProdukStock menambah stok produk dan mencatat mutasinya ke kartu stok
+func (rp *mongoRepoProduk) IncreaseProdukStock(ctx context.Context, id string, kuantitas int, ref domain.StockRef) error {
+	DataProduk := rp.DB.Collection(_Produk)
+
 	if kuantitas <= 0 {
 		return fmt.Errorf("kuantitas harus lebih dari 0")
 	}
@@ -241,45 +892,321 @@ func (rp *mongoRepoProduk) IncreaseProdukStock(ctx context.Context, id string, k
 	update := bson.M{
 		"$inc": bson.M{
 			"stok_barang": kuantitas,
+			"version":     1,
 		},
 		"$set": bson.M{
 			"updated_at": time.Now(),
 		},
 	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var updated domain.Produk
+	err := DataProduk.FindOneAndUpdate(ctx, bson.M{"_id": id}, update, opts).Decode(&updated)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return fmt.Errorf("produk dengan ID %s tidak ditemukan", id)
+		}
+		return fmt.Errorf("gagal menambah stok produk: %w", err)
+	}
+
+	return rp.Movement.Record(ctx, domain.StockMovement{
+		IDProduk: id,
+		Delta:    kuantitas,
+		Saldo:    updated.Stok,
+		Reason:   ref.Reason,
+		RefID:    ref.RefID,
+		User:     ref.User,
+	})
+}
+
+// AdjustProdukStock menyesuaikan stok dengan delta tanpa syarat stok cukup, lalu mencatat
+// mutasinya ke kartu stok. Dipakai untuk penyesuaian (misal opname), bukan penjualan,
+// sehingga stok boleh menjadi negatif bila transaksi setelah hitung fisik melebihi stok.
+func (rp *mongoRepoProduk) AdjustProdukStock(ctx context.Context, id string, delta int, ref domain.StockRef) error {
+	DataProduk := rp.DB.Collection(_Produk)
+
+	if delta == 0 {
+		return fmt.Errorf("delta stok tidak boleh 0")
+	}
+
+	update := bson.M{
+		"$inc": bson.M{"stok_barang": delta, "version": 1},
+		"$set": bson.M{"updated_at": time.Now()},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var updated domain.Produk
+	err := DataProduk.FindOneAndUpdate(ctx, bson.M{"_id": id, "is_deleted": nil}, update, opts).Decode(&updated)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return fmt.Errorf("produk dengan ID %s tidak ditemukan", id)
+		}
+		return fmt.Errorf("gagal menyesuaikan stok produk: %w", err)
+	}
 
-	_, err = DataProduk.UpdateOne(ctx, bson.M{"_id": id}, update)
+	err = rp.Movement.Record(ctx, domain.StockMovement{
+		IDProduk: id,
+		Delta:    delta,
+		Saldo:    updated.Stok,
+		Reason:   ref.Reason,
+		RefID:    ref.RefID,
+		User:     ref.User,
+	})
 	if err != nil {
-		return fmt.Errorf("gagal menambah stok produk: %v", err)
+		return err
 	}
 
-	log.Printf("Berhasil menambah stok produk %s sebanyak %d", id, kuantitas)
+	if delta < 0 {
+		rp.notifyLowStock(ctx, updated, -delta, ref)
+	}
 	return nil
 }
 
-// ImportData mengimpor data produk secara batch
-func (rp *mongoRepoProduk) ImportData(ctx context.Context, produkList []domain.Produk) error {
-	if len(produkList) == 0 {
+// SetHargaPokok memperbarui HPP produk tanpa mengubah versi yang dipakai client untuk edit
+func (rp *mongoRepoProduk) SetHargaPokok(ctx context.Context, id string, hargaPokok int) error {
+	DataProduk := rp.DB.Collection(_Produk)
+
+	if hargaPokok < 0 {
+		return fmt.Errorf("harga pokok tidak boleh negatif")
+	}
+
+	result, err := DataProduk.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$set": bson.M{"harga_pokok": hargaPokok},
+	})
+	if err != nil {
+		return fmt.Errorf("gagal memperbarui harga pokok: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("produk dengan ID %s tidak ditemukan", id)
+	}
+
+	return nil
+}
+
+// SetHarga mengubah harga jual produk dan mencatatnya ke riwayat harga
+func (rp *mongoRepoProduk) SetHarga(ctx context.Context, id string, harga int, ref domain.PriceRef) error {
+	DataProduk := rp.DB.Collection(_Produk)
+
+	if harga <= 0 {
+		return fmt.Errorf("harga harus lebih dari 0")
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"harga":      harga,
+			"updated_at": time.Now(),
+		},
+		"$inc": bson.M{"version": 1},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.Before)
+
+	var previous domain.Produk
+	err := DataProduk.FindOneAndUpdate(ctx, bson.M{"_id": id, "is_deleted": nil}, update, opts).Decode(&previous)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return fmt.Errorf("produk dengan ID %s tidak ditemukan", id)
+		}
+		return fmt.Errorf("gagal mengubah harga produk: %v", err)
+	}
+
+	if previous.Harga == harga {
 		return nil
 	}
 
+	return rp.Price.Record(ctx, domain.PriceHistory{
+		IDProduk:  id,
+		HargaLama: previous.Harga,
+		HargaBaru: harga,
+		Source:    ref.Source,
+		RefID:     ref.RefID,
+		User:      ref.User,
+	})
+}
+
+// AddGambar menambahkan gambar di urutan terakhir
+func (rp *mongoRepoProduk) AddGambar(ctx context.Context, id string, gambar domain.ProdukGambar) error {
 	DataProduk := rp.DB.Collection(_Produk)
 
-	// 1. Dapatkan ID terakhir dari database
-	var lastProduct domain.Produk
-	err := DataProduk.FindOne(ctx, bson.M{}, options.FindOne().SetSort(bson.M{"_id": -1})).Decode(&lastProduct)
-	if err != nil && err != mongo.ErrNoDocuments {
-		return fmt.Errorf("error finding last product: %v", err)
+	result, err := DataProduk.UpdateOne(ctx, bson.M{"_id": id, "is_deleted": nil}, bson.M{
+		"$push": bson.M{"gambar": gambar},
+		"$set":  bson.M{"updated_at": time.Now()},
+		"$inc":  bson.M{"version": 1},
+	})
+	if err != nil {
+		return fmt.Errorf("gagal menambahkan gambar produk: %v", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("produk dengan ID %s tidak ditemukan", id)
+	}
+
+	return nil
+}
+
+// RemoveGambar menghapus gambar dari produk dan mengembalikan data gambar yang dihapus
+func (rp *mongoRepoProduk) RemoveGambar(ctx context.Context, id string, idGambar primitive.ObjectID) (*domain.ProdukGambar, error) {
+	DataProduk := rp.DB.Collection(_Produk)
+
+	filter := bson.M{"_id": id, "gambar.id_gambar": idGambar}
+	update := bson.M{
+		"$pull": bson.M{"gambar": bson.M{"id_gambar": idGambar}},
+		"$set":  bson.M{"updated_at": time.Now()},
+		"$inc":  bson.M{"version": 1},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.Before)
+
+	var previous domain.Produk
+	err := DataProduk.FindOneAndUpdate(ctx, filter, update, opts).Decode(&previous)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("gambar %s tidak ditemukan pada produk %s", idGambar.Hex(), id)
+		}
+		return nil, fmt.Errorf("gagal menghapus gambar produk: %v", err)
+	}
+
+	for _, gambar := range previous.Gambar {
+		if gambar.ID == idGambar {
+			return &gambar, nil
+		}
+	}
+	return nil, fmt.Errorf("gambar %s tidak ditemukan pada produk %s", idGambar.Hex(), id)
+}
+
+// ReorderGambar menyusun ulang gambar produk; ids harus berisi semua gambar tepat satu kali
+func (rp *mongoRepoProduk) ReorderGambar(ctx context.Context, id string, ids []primitive.ObjectID) ([]domain.ProdukGambar, error) {
+	DataProduk := rp.DB.Collection(_Produk)
+
+	var current domain.Produk
+	err := DataProduk.FindOne(ctx, bson.M{"_id": id, "is_deleted": nil}).Decode(&current)
+	if err == mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("produk dengan ID %s tidak ditemukan", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("gagal untuk mendapatkan produk: %w", err)
 	}
 
-	// Tentukan ID awal
-	startID := 1
-	if err != mongo.ErrNoDocuments {
-		lastIDNum, err := strconv.Atoi(lastProduct.IDProduk)
+	if len(ids) != len(current.Gambar) {
+		return nil, fmt.Errorf("urutan harus berisi semua %d gambar produk", len(current.Gambar))
+	}
+
+	byID := make(map[primitive.ObjectID]domain.ProdukGambar, len(current.Gambar))
+	for _, gambar := range current.Gambar {
+		byID[gambar.ID] = gambar
+	}
+
+	ordered := make([]domain.ProdukGambar, 0, len(ids))
+	for _, idGambar := range ids {
+		gambar, ok := byID[idGambar]
+		if !ok {
+			return nil, fmt.Errorf("gambar %s tidak ditemukan atau duplikat", idGambar.Hex())
+		}
+		delete(byID, idGambar)
+		ordered = append(ordered, gambar)
+	}
+
+	// Filter dengan isi gambar lama agar unggahan/hapus yang bersamaan tidak tertimpa
+	result, err := DataProduk.UpdateOne(ctx, bson.M{"_id": id, "gambar": current.Gambar}, bson.M{
+		"$set": bson.M{
+			"gambar":     ordered,
+			"updated_at": time.Now(),
+		},
+		"$inc": bson.M{"version": 1},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengubah urutan gambar: %v", err)
+	}
+	if result.MatchedCount == 0 {
+		return nil, fmt.Errorf("gambar produk berubah saat diurutkan, silakan coba lagi")
+	}
+
+	return ordered, nil
+}
+
+// nextBarcode membuat EAN-13 internal dari counter dan melewati kode yang sudah dipakai produk lain
+func (rp *mongoRepoProduk) nextBarcode(ctx context.Context) (string, error) {
+	DataProduk := rp.DB.Collection(_Produk)
+
+	for {
+		seq, err := rp.Counter.NextSequence(ctx, _BarcodeCounter, 1)
+		if err != nil {
+			return "", err
+		}
+		code, err := domain.InternalEAN13(rp.barcodePrefix, seq)
 		if err != nil {
-			return fmt.Errorf("error parsing last ID: %v", err)
+			return "", err
+		}
+
+		used, err := DataProduk.CountDocuments(ctx, bson.M{"barcode_produk": code})
+		if err != nil {
+			return "", fmt.Errorf("gagal memeriksa barcode: %v", err)
+		}
+		if used == 0 {
+			return code, nil
 		}
-		startID = lastIDNum + 1
 	}
+}
+
+// GenerateBarcodes memberi EAN-13 internal ke semua produk aktif yang belum punya barcode
+func (rp *mongoRepoProduk) GenerateBarcodes(ctx context.Context) ([]domain.ProdukBarcode, error) {
+	DataProduk := rp.DB.Collection(_Produk)
+
+	missing := bson.M{"$in": bson.A{"", nil}}
+	opts := options.Find().
+		SetProjection(bson.M{"nama_produk": 1}).
+		SetSort(bson.M{"_id": 1})
+	cursor, err := DataProduk.Find(ctx, bson.M{"barcode_produk": missing, "is_deleted": nil}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("gagal untuk mendapatkan produk tanpa barcode: %v", err)
+	}
+
+	var products []domain.Produk
+	if err := cursor.All(ctx, &products); err != nil {
+		return nil, fmt.Errorf("gagal untuk mendapatkan produk tanpa barcode: %v", err)
+	}
+
+	assigned := make([]domain.ProdukBarcode, 0, len(products))
+	for _, produk := range products {
+		code, err := rp.nextBarcode(ctx)
+		if err != nil {
+			return assigned, err
+		}
+
+		// Filter barcode kosong agar barcode yang baru diisi manual tidak tertimpa
+		result, err := DataProduk.UpdateOne(ctx, bson.M{"_id": produk.IDProduk, "barcode_produk": missing}, bson.M{
+			"$set": bson.M{
+				"barcode_produk": code,
+				"updated_at":     time.Now(),
+			},
+			"$inc": bson.M{"version": 1},
+		})
+		if err != nil {
+			return assigned, fmt.Errorf("gagal menyimpan barcode produk %s: %v", produk.IDProduk, err)
+		}
+		if result.ModifiedCount == 0 {
+			continue
+		}
+
+		assigned = append(assigned, domain.ProdukBarcode{
+			IDProduk:   produk.IDProduk,
+			NamaProduk: produk.NamaProduk,
+			KodeProduk: code,
+		})
+	}
+
+	return assigned, nil
+}
+
+// GetStockMovements menampilkan kartu stok sebuah produk
+func (rp *mongoRepoProduk) GetStockMovements(ctx context.Context, id string, from, to time.Time) ([]domain.StockMovement, error) {
+	return rp.Movement.GetByProduk(ctx, id, from, to)
+}
+
+// ImportData mengimpor data produk secara batch
+func (rp *mongoRepoProduk) ImportData(ctx context.Context, produkList []domain.Produk) error {
+	if len(produkList) == 0 {
+		return nil
+	}
+
+	DataProduk := rp.DB.Collection(_Produk)
 
 	// 2. Cek duplikat barcode
 	barcodes := make([]string, 0)
@@ -310,13 +1237,14 @@ func (rp *mongoRepoProduk) ImportData(ctx context.Context, produkList []domain.P
 		}
 	}
 
-	// 3. Siapkan dokumen untuk bulk insert
-	var operations []mongo.WriteModel
+	// 3. Validasi data dan siapkan dokumen untuk bulk insert
+	var validProduk []domain.Produk
 	skippedCount := 0
-	currentID := startID
-	now := time.Now()
 
-	log.Printf("Starting import with ID: %d", currentID)
+	kategoriIndex, err := loadKategoriIndex(ctx, rp.DB)
+	if err != nil {
+		return err
+	}
 
 	for i, produk := range produkList {
 		// Validasi data
@@ -333,30 +1261,65 @@ func (rp *mongoRepoProduk) ImportData(ctx context.Context, produkList []domain.P
 			continue
 		}
 
-		// Set ID dan metadata
-		idStr := fmt.Sprintf("%03d", currentID)
-		log.Printf("Assigning ID %s to product %s", idStr, produk.NamaProduk)
-
-		doc := bson.D{
-			{Key: "_id", Value: idStr},
-			{Key: "nama_produk", Value: produk.NamaProduk},
-			{Key: "kategori", Value: produk.Kategori},
-			{Key: "sub_kategori", Value: produk.SubKategori},
-			{Key: "barcode_produk", Value: produk.KodeProduk},
-			{Key: "harga", Value: produk.Harga},
-			{Key: "stok_barang", Value: produk.Stok},
-			{Key: "updated_at", Value: now},
-			{Key: "is_deleted", Value: nil},
+		// Validasi sama dengan CreateProduk: supplier, satuan, tier, pajak, varian, kategori, dan komponen
+		produk.IDProduk = ""
+		if err := rp.validateProduk(ctx, &produk, kategoriIndex); err != nil {
+			log.Printf("Skip produk #%d: %v", i+1, err)
+			skippedCount++
+			continue
 		}
 
-		operation := mongo.NewInsertOneModel().SetDocument(doc)
-		operations = append(operations, operation)
-		currentID++
+		validProduk = append(validProduk, produk)
 
 		// Tandai barcode sebagai sudah digunakan
 		existingBarcodes[produk.KodeProduk] = true
 	}
 
+	var operations []mongo.WriteModel
+	var movements []domain.StockMovement
+	var prices []domain.PriceHistory
+	if len(validProduk) > 0 {
+		// Pesan ID sekaligus untuk semua produk valid
+		ids, err := rp.reserveIDs(ctx, len(validProduk))
+		if err != nil {
+			return fmt.Errorf("error generating IDs: %v", err)
+		}
+
+		now := time.Now()
+		user := domain.UserFromContext(ctx)
+		for i, produk := range validProduk {
+			log.Printf("Assigning ID %s to product %s", ids[i], produk.NamaProduk)
+
+			// Simpan seluruh field produk seperti CreateProduk; gambar hanya lewat unggahan
+			produk.IDProduk = ids[i]
+			produk.Gambar = nil
+			produk.Version = 1
+			produk.UpdatedAt = now
+			produk.IsDeleted = nil
+
+			operation := mongo.NewInsertOneModel().SetDocument(produk)
+			operations = append(operations, operation)
+
+			prices = append(prices, domain.PriceHistory{
+				IDProduk:  ids[i],
+				HargaBaru: produk.Harga,
+				Source:    domain.PriceSourceImport,
+				User:      user,
+				Timestamp: now,
+			})
+
+			movements = append(movements, domain.StockMovement{
+				IDProduk:  ids[i],
+				Delta:     produk.Stok,
+				Saldo:     produk.Stok,
+				Reason:    domain.StockReasonImport,
+				RefID:     ids[i],
+				User:      user,
+				Timestamp: now,
+			})
+		}
+	}
+
 	if len(operations) == 0 {
 		if skippedCount > 0 {
 			return fmt.Errorf("semua produk (%d) dilewati karena duplikat atau tidak valid", skippedCount)
@@ -364,24 +1327,51 @@ func (rp *mongoRepoProduk) ImportData(ctx context.Context, produkList []domain.P
 		return fmt.Errorf("tidak ada data valid yang dapat diimpor")
 	}
 
-	// 4. Lakukan bulk insert
-	bulkOpts := options.BulkWrite().SetOrdered(true)
-	result, err := DataProduk.BulkWrite(ctx, operations, bulkOpts)
+	sesi, err := rp.DB.Client().StartSession()
 	if err != nil {
-		if bulkErr, ok := err.(mongo.BulkWriteException); ok {
-			for _, writeErr := range bulkErr.WriteErrors {
-				log.Printf("Error pada dokumen %d: %v", writeErr.Index, writeErr.Message)
-			}
-			if result != nil && result.InsertedCount > 0 {
-				log.Printf("Berhasil mengimpor sebagian: %d dari %d dokumen",
-					result.InsertedCount, len(operations))
-				return nil
+		return fmt.Errorf("gagal memulai sesi: %v", err)
+	}
+	defer sesi.EndSession(ctx)
+
+	// 4. Bulk insert, stok awal, dan harga awal berjalan dalam satu transaksi
+	// sehingga tidak ada produk hasil import tanpa kartu stok dan riwayat harga
+	var insertedCount int64
+	_, err = sesi.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		bulkOpts := options.BulkWrite().SetOrdered(true)
+		result, err := DataProduk.BulkWrite(sc, operations, bulkOpts)
+		if err != nil {
+			if bulkErr, ok := err.(mongo.BulkWriteException); ok {
+				for _, writeErr := range bulkErr.WriteErrors {
+					log.Printf("Error pada dokumen %d: %v", writeErr.Index, writeErr.Message)
+				}
 			}
+			return nil, fmt.Errorf("gagal mengimpor data: %w", err)
 		}
-		return fmt.Errorf("gagal mengimpor data: %v", err)
+		insertedCount = result.InsertedCount
+
+		return nil, rp.recordImportHistory(sc, movements, prices)
+	})
+	if err != nil {
+		return err
 	}
 
 	log.Printf("Berhasil mengimpor %d produk, %d produk dilewati",
-		result.InsertedCount, skippedCount)
+		insertedCount, skippedCount)
 	return nil
 }
+
+// recordImportHistory mencatat stok awal dan harga awal produk hasil import
+func (rp *mongoRepoProduk) recordImportHistory(ctx context.Context, movements []domain.StockMovement, prices []domain.PriceHistory) error {
+	if err := rp.Price.Record(ctx, prices...); err != nil {
+		return err
+	}
+
+	withStock := make([]domain.StockMovement, 0, len(movements))
+	for _, movement := range movements {
+		if movement.Delta != 0 {
+			withStock = append(withStock, movement)
+		}
+	}
+
+	return rp.Movement.Record(ctx, withStock...)
+}
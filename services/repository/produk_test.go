@@ -118,6 +118,7 @@ func TestUpdateProduk(t *testing.T) {
 		SubKategori: "SubKategoriTest",
 		Stok:        20,
 		KodeProduk:  "0001",
+		Version:     produk.Version,
 	}
 	err := repo.UpdateProduk(context.Background(), &updatedProduk)
 	assert.NoError(t, err)
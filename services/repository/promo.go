@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"SIE-SRC/domain"
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoRepoPromo struct {
+	DB      *mongo.Database
+	Counter domain.CounterRepository
+}
+
+func NewMongoRepoPromo(client *mongo.Database) domain.PromoRepository {
+	return &mongoRepoPromo{
+		DB:      client,
+		Counter: NewMongoRepoCounter(client),
+	}
+}
+
+var _Promo = "promo"
+
+var promoIDFormat = domain.CounterFormat{Prefix: "PR", Width: 3}
+
+// Create menambahkan promo baru
+func (rp *mongoRepoPromo) Create(ctx context.Context, bd *domain.Promo) (domain.Promo, error) {
+	DataPromo := rp.DB.Collection(_Promo)
+
+	if err := bd.Validate(); err != nil {
+		return domain.Promo{}, err
+	}
+
+	seq, err := rp.Counter.NextSequence(ctx, _Promo, 1)
+	if err != nil {
+		return domain.Promo{}, fmt.Errorf("gagal generate ID promo: %v", err)
+	}
+	bd.IDPromo = formatSequenceID(promoIDFormat, seq)
+	bd.UpdatedAt = time.Now()
+
+	if _, err := DataPromo.InsertOne(ctx, bd); err != nil {
+		return domain.Promo{}, fmt.Errorf("gagal menyimpan promo: %v", err)
+	}
+
+	return *bd, nil
+}
+
+func (rp *mongoRepoPromo) find(ctx context.Context, filter bson.M) ([]domain.Promo, error) {
+	DataPromo := rp.DB.Collection(_Promo)
+
+	cursor, err := DataPromo.Find(ctx, filter, options.Find().SetSort(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	list := make([]domain.Promo, 0)
+	if err := cursor.All(ctx, &list); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// GetAll menampilkan semua promo
+func (rp *mongoRepoPromo) GetAll(ctx context.Context) ([]domain.Promo, error) {
+	return rp.find(ctx, bson.M{})
+}
+
+// GetAktif menampilkan promo yang aktif; periode dan happy hour dicek dengan Promo.BerlakuPada
+func (rp *mongoRepoPromo) GetAktif(ctx context.Context) ([]domain.Promo, error) {
+	list, err := rp.find(ctx, bson.M{"aktif": true})
+	if err != nil {
+		return nil, fmt.Errorf("gagal mendapatkan promo aktif: %v", err)
+	}
+	return list, nil
+}
+
+// GetByID mendapatkan promo berdasarkan ID
+func (rp *mongoRepoPromo) GetByID(ctx context.Context, id string) (*domain.Promo, error) {
+	DataPromo := rp.DB.Collection(_Promo)
+
+	var promo domain.Promo
+	err := DataPromo.FindOne(ctx, bson.M{"_id": id}).Decode(&promo)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("promo dengan ID %s tidak ditemukan", id)
+		}
+		return nil, fmt.Errorf("gagal mendapatkan promo: %v", err)
+	}
+
+	return &promo, nil
+}
+
+// Update mengganti aturan promo; penjualan lama tetap menyimpan diskon yang sudah dihitung
+func (rp *mongoRepoPromo) Update(ctx context.Context, bd *domain.Promo) error {
+	DataPromo := rp.DB.Collection(_Promo)
+
+	if err := bd.Validate(); err != nil {
+		return err
+	}
+
+	bd.UpdatedAt = time.Now()
+	result, err := DataPromo.ReplaceOne(ctx, bson.M{"_id": bd.IDPromo}, bd)
+	if err != nil {
+		return fmt.Errorf("gagal memperbarui promo: %v", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("promo dengan ID %s tidak ditemukan", bd.IDPromo)
+	}
+
+	return nil
+}
+
+// Delete menghapus promo
+func (rp *mongoRepoPromo) Delete(ctx context.Context, id string) error {
+	DataPromo := rp.DB.Collection(_Promo)
+
+	result, err := DataPromo.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return fmt.Errorf("gagal menghapus promo: %v", err)
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("promo dengan ID %s tidak ditemukan", id)
+	}
+
+	return nil
+}
@@ -0,0 +1,383 @@
+package repository
+
+import (
+	"SIE-SRC/domain"
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoRepoPurchase struct {
+	DB           *mongo.Database
+	RepoProduk   domain.ProdukRepository
+	RepoSupplier domain.SupplierRepository
+	RepoBatch    domain.StockBatchRepository
+	Counter      domain.CounterRepository
+}
+
+func NewMongoRepoPurchase(client *mongo.Database, produkRepo domain.ProdukRepository, supplierRepo domain.SupplierRepository) domain.PurchaseRepository {
+	return &mongoRepoPurchase{
+		DB:           client,
+		RepoProduk:   produkRepo,
+		RepoSupplier: supplierRepo,
+		RepoBatch:    NewMongoRepoStockBatch(client),
+		Counter:      NewMongoRepoCounter(client),
+	}
+}
+
+var (
+	_PurchaseOrder = "purchase_order"
+	_GoodsReceipt  = "goods_receipt"
+)
+
+var (
+	poIDFormat      = domain.CounterFormat{Prefix: "PO", Width: 3}
+	receiptIDFormat = domain.CounterFormat{Prefix: "GR", Width: 3}
+)
+
+// CreatePO membuat purchase order baru ke supplier
+func (rp *mongoRepoPurchase) CreatePO(ctx context.Context, bd *domain.PurchaseOrder) (domain.PurchaseOrder, error) {
+	DataPO := rp.DB.Collection(_PurchaseOrder)
+
+	if bd.IDSupplier == "" {
+		return domain.PurchaseOrder{}, fmt.Errorf("id supplier tidak boleh kosong")
+	}
+	if len(bd.Items) == 0 {
+		return domain.PurchaseOrder{}, fmt.Errorf("minimal harus ada satu produk")
+	}
+
+	supplier, err := rp.RepoSupplier.GetByID(ctx, bd.IDSupplier)
+	if err != nil {
+		return domain.PurchaseOrder{}, err
+	}
+
+	seen := make(map[string]bool, len(bd.Items))
+	total := 0
+	for i, item := range bd.Items {
+		if item.IDProduk == "" {
+			return domain.PurchaseOrder{}, fmt.Errorf("id produk tidak boleh kosong pada produk ke-%d", i+1)
+		}
+		if seen[item.IDProduk] {
+			return domain.PurchaseOrder{}, fmt.Errorf("produk %s muncul lebih dari sekali", item.IDProduk)
+		}
+		seen[item.IDProduk] = true
+
+		if item.JumlahPesan <= 0 {
+			return domain.PurchaseOrder{}, fmt.Errorf("jumlah pesan harus lebih dari 0 pada produk ke-%d", i+1)
+		}
+		if item.HargaBeli < 0 {
+			return domain.PurchaseOrder{}, fmt.Errorf("harga beli tidak boleh negatif pada produk ke-%d", i+1)
+		}
+
+		produk, err := rp.RepoProduk.GetProdukById(ctx, item.IDProduk)
+		if err != nil {
+			return domain.PurchaseOrder{}, fmt.Errorf("gagal mendapatkan info produk: %v", err)
+		}
+
+		if produk.IsKit() {
+			return domain.PurchaseOrder{}, fmt.Errorf("produk paket %s tidak dapat dipesan, pesan komponennya", produk.NamaProduk)
+		}
+
+		// Barang boleh dipesan dalam satuan besar (misal dus); stok tetap dicatat dalam satuan dasar
+		satuan, err := produk.ResolveSatuan(item.Satuan)
+		if err != nil {
+			return domain.PurchaseOrder{}, err
+		}
+
+		bd.Items[i].NamaProduk = produk.NamaProduk
+		bd.Items[i].Satuan = satuan.Nama
+		bd.Items[i].Konversi = satuan.Konversi
+		bd.Items[i].JumlahDiterima = 0
+		bd.Items[i].Subtotal = item.HargaBeli * item.JumlahPesan
+		total += bd.Items[i].Subtotal
+	}
+
+	seq, err := rp.Counter.NextSequence(ctx, _PurchaseOrder, 1)
+	if err != nil {
+		return domain.PurchaseOrder{}, fmt.Errorf("gagal generate ID PO: %v", err)
+	}
+
+	now := time.Now()
+	bd.IDPO = formatSequenceID(poIDFormat, seq)
+	bd.NamaSupplier = supplier.NamaSupplier
+	bd.Status = domain.POStatusOpen
+	bd.Total = total
+	bd.DibuatOleh = domain.UserFromContext(ctx)
+	bd.UpdatedAt = now
+	if bd.Tanggal.IsZero() {
+		bd.Tanggal = now
+	}
+
+	if _, err := DataPO.InsertOne(ctx, bd); err != nil {
+		return domain.PurchaseOrder{}, fmt.Errorf("gagal menyimpan purchase order: %v", err)
+	}
+
+	return *bd, nil
+}
+
+// GetAllPO menampilkan semua purchase order, terbaru lebih dulu
+func (rp *mongoRepoPurchase) GetAllPO(ctx context.Context) ([]domain.PurchaseOrder, error) {
+	DataPO := rp.DB.Collection(_PurchaseOrder)
+
+	cursor, err := DataPO.Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"tanggal": -1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	list := make([]domain.PurchaseOrder, 0)
+	if err := cursor.All(ctx, &list); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// GetPOByID mendapatkan purchase order berdasarkan ID
+func (rp *mongoRepoPurchase) GetPOByID(ctx context.Context, id string) (*domain.PurchaseOrder, error) {
+	DataPO := rp.DB.Collection(_PurchaseOrder)
+
+	var po domain.PurchaseOrder
+	err := DataPO.FindOne(ctx, bson.M{"_id": id}).Decode(&po)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("purchase order dengan ID %s tidak ditemukan", id)
+		}
+		return nil, fmt.Errorf("gagal mengambil purchase order: %v", err)
+	}
+
+	return &po, nil
+}
+
+// CancelPO membatalkan purchase order yang belum diterima sama sekali
+func (rp *mongoRepoPurchase) CancelPO(ctx context.Context, id string) error {
+	DataPO := rp.DB.Collection(_PurchaseOrder)
+
+	result, err := DataPO.UpdateOne(ctx, bson.M{"_id": id, "status": domain.POStatusOpen}, bson.M{
+		"$set": bson.M{
+			"status":     domain.POStatusCancelled,
+			"updated_at": time.Now(),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("gagal membatalkan purchase order: %v", err)
+	}
+
+	if result.MatchedCount == 0 {
+		po, err := rp.GetPOByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("purchase order %s berstatus %s dan tidak dapat dibatalkan", id, po.Status)
+	}
+
+	return nil
+}
+
+// Receive mencatat penerimaan barang atas PO dan menambah stok produk dalam satu transaksi.
+// Penerimaan boleh sebagian; status PO menjadi received setelah semua item diterima penuh.
+func (rp *mongoRepoPurchase) Receive(ctx context.Context, idPO string, bd *domain.GoodsReceipt) (domain.GoodsReceipt, error) {
+	DataPO := rp.DB.Collection(_PurchaseOrder)
+	DataReceipt := rp.DB.Collection(_GoodsReceipt)
+
+	if len(bd.Items) == 0 {
+		return domain.GoodsReceipt{}, fmt.Errorf("minimal harus ada satu produk yang diterima")
+	}
+
+	// ID penerimaan dipesan di luar transaksi agar penerimaan paralel tidak saling konflik
+	// pada dokumen counter; nomor yang terpakai oleh transaksi gagal dibiarkan terlewat
+	seq, err := rp.Counter.NextSequence(ctx, _GoodsReceipt, 1)
+	if err != nil {
+		return domain.GoodsReceipt{}, fmt.Errorf("gagal generate ID penerimaan: %v", err)
+	}
+	bd.IDPenerimaan = formatSequenceID(receiptIDFormat, seq)
+
+	sesi, err := rp.DB.Client().StartSession()
+	if err != nil {
+		return domain.GoodsReceipt{}, fmt.Errorf("gagal memulai sesi: %v", err)
+	}
+	defer sesi.EndSession(ctx)
+
+	// WithTransaction mengulang transaksi yang gagal karena write conflict (TransientTransactionError)
+	_, err = sesi.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		var po domain.PurchaseOrder
+		err := DataPO.FindOne(sc, bson.M{"_id": idPO}).Decode(&po)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				return nil, fmt.Errorf("purchase order dengan ID %s tidak ditemukan", idPO)
+			}
+			return nil, fmt.Errorf("gagal mengambil purchase order: %w", err)
+		}
+
+		if po.Status != domain.POStatusOpen && po.Status != domain.POStatusPartial {
+			return nil, fmt.Errorf("purchase order %s berstatus %s dan tidak dapat diterima", idPO, po.Status)
+		}
+
+		lineIndex := make(map[string]int, len(po.Items))
+		for i, line := range po.Items {
+			lineIndex[line.IDProduk] = i
+		}
+
+		ref := domain.StockRef{
+			Reason: domain.StockReasonPurchase,
+			RefID:  bd.IDPenerimaan,
+			User:   domain.UserFromContext(ctx),
+		}
+
+		total := 0
+		for i, item := range bd.Items {
+			idx, ok := lineIndex[item.IDProduk]
+			if !ok {
+				return nil, fmt.Errorf("produk %s tidak ada di purchase order %s", item.IDProduk, idPO)
+			}
+			line := &po.Items[idx]
+
+			if item.Jumlah <= 0 {
+				return nil, fmt.Errorf("jumlah diterima harus lebih dari 0 pada produk ke-%d", i+1)
+			}
+			sisa := line.JumlahPesan - line.JumlahDiterima
+			if item.Jumlah > sisa {
+				return nil, fmt.Errorf("jumlah diterima produk %s melebihi sisa pesanan (sisa: %d, diterima: %d)",
+					line.NamaProduk, sisa, item.Jumlah)
+			}
+
+			// Harga beli aktual boleh berbeda dari harga di PO
+			if item.HargaBeli <= 0 {
+				item.HargaBeli = line.HargaBeli
+			}
+
+			// Jumlah dan harga beli mengikuti satuan baris PO, HPP dan stok dalam satuan dasar
+			konversi := line.Konversi
+			if konversi <= 0 {
+				konversi = 1
+			}
+			jumlahDasar := item.Jumlah * konversi
+			hargaDasar := (item.HargaBeli + konversi/2) / konversi
+
+			produk, err := rp.RepoProduk.GetProdukById(sc, item.IDProduk)
+			if err != nil {
+				return nil, err
+			}
+			hpp := movingAverageCost(produk.Stok, produk.HargaPokok, jumlahDasar, hargaDasar)
+			if err := rp.RepoProduk.SetHargaPokok(sc, item.IDProduk, hpp); err != nil {
+				return nil, err
+			}
+
+			err = rp.RepoProduk.IncreaseProdukStock(sc, item.IDProduk, jumlahDasar, ref)
+			if err != nil {
+				return nil, fmt.Errorf("gagal menambah stok produk %s: %w", item.IDProduk, err)
+			}
+
+			// Barang dengan tanggal kadaluarsa dicatat sebagai batch baru
+			if item.TanggalKadaluarsa != nil && !item.TanggalKadaluarsa.IsZero() {
+				_, err := rp.RepoBatch.Create(sc, &domain.StockBatch{
+					IDProduk:          item.IDProduk,
+					NoBatch:           item.NoBatch,
+					TanggalKadaluarsa: *item.TanggalKadaluarsa,
+					Jumlah:            jumlahDasar,
+					RefID:             bd.IDPenerimaan,
+				})
+				if err != nil {
+					return nil, fmt.Errorf("gagal mencatat batch produk %s: %w", item.IDProduk, err)
+				}
+			}
+
+			line.JumlahDiterima += item.Jumlah
+
+			bd.Items[i].NamaProduk = line.NamaProduk
+			bd.Items[i].Satuan = line.Satuan
+			bd.Items[i].HargaBeli = item.HargaBeli
+			bd.Items[i].Subtotal = item.HargaBeli * item.Jumlah
+			total += bd.Items[i].Subtotal
+		}
+
+		po.Status = domain.POStatusReceived
+		for _, line := range po.Items {
+			if line.JumlahDiterima < line.JumlahPesan {
+				po.Status = domain.POStatusPartial
+				break
+			}
+		}
+
+		_, err = DataPO.UpdateOne(sc, bson.M{"_id": idPO}, bson.M{
+			"$set": bson.M{
+				"items":      po.Items,
+				"status":     po.Status,
+				"updated_at": time.Now(),
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("gagal memperbarui purchase order: %w", err)
+		}
+
+		bd.IDPO = idPO
+		bd.IDSupplier = po.IDSupplier
+		bd.Total = total
+		bd.DiterimaOleh = ref.User
+		if bd.Tanggal.IsZero() {
+			bd.Tanggal = time.Now()
+		}
+
+		if _, err := DataReceipt.InsertOne(sc, bd); err != nil {
+			return nil, fmt.Errorf("gagal menyimpan penerimaan barang: %w", err)
+		}
+
+		return nil, nil
+	})
+	if err != nil {
+		return domain.GoodsReceipt{}, err
+	}
+
+	return *bd, nil
+}
+
+// movingAverageCost menghitung HPP rata-rata bergerak setelah penerimaan barang.
+// HPP lama 0 dianggap belum diketahui sehingga langsung memakai harga beli,
+// dan stok negatif tidak ikut dihitung sebagai nilai persediaan.
+func movingAverageCost(stok, hpp, jumlah, hargaBeli int) int {
+	if hpp <= 0 || stok <= 0 {
+		return hargaBeli
+	}
+
+	nilai := int64(stok)*int64(hpp) + int64(jumlah)*int64(hargaBeli)
+	total := int64(stok + jumlah)
+	return int((nilai + total/2) / total)
+}
+
+// GetAllReceipt menampilkan semua penerimaan barang, terbaru lebih dulu
+func (rp *mongoRepoPurchase) GetAllReceipt(ctx context.Context) ([]domain.GoodsReceipt, error) {
+	DataReceipt := rp.DB.Collection(_GoodsReceipt)
+
+	cursor, err := DataReceipt.Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"tanggal": -1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	list := make([]domain.GoodsReceipt, 0)
+	if err := cursor.All(ctx, &list); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// GetReceiptByID mendapatkan penerimaan barang berdasarkan ID
+func (rp *mongoRepoPurchase) GetReceiptByID(ctx context.Context, id string) (*domain.GoodsReceipt, error) {
+	DataReceipt := rp.DB.Collection(_GoodsReceipt)
+
+	var receipt domain.GoodsReceipt
+	err := DataReceipt.FindOne(ctx, bson.M{"_id": id}).Decode(&receipt)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("penerimaan barang dengan ID %s tidak ditemukan", id)
+		}
+		return nil, fmt.Errorf("gagal mengambil penerimaan barang: %v", err)
+	}
+
+	return &receipt, nil
+}
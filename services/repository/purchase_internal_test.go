@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMovingAverageCost(t *testing.T) {
+	tests := []struct {
+		name      string
+		stok      int
+		hpp       int
+		jumlah    int
+		hargaBeli int
+		want      int
+	}{
+		{name: "stok kosong memakai harga beli", stok: 0, hpp: 1000, jumlah: 10, hargaBeli: 1200, want: 1200},
+		{name: "hpp belum diketahui memakai harga beli", stok: 10, hpp: 0, jumlah: 10, hargaBeli: 1200, want: 1200},
+		{name: "stok negatif tidak dihitung", stok: -5, hpp: 1000, jumlah: 10, hargaBeli: 1200, want: 1200},
+		{name: "rata-rata tertimbang", stok: 10, hpp: 1000, jumlah: 10, hargaBeli: 1200, want: 1100},
+		{name: "bobot stok lama lebih besar", stok: 30, hpp: 1000, jumlah: 10, hargaBeli: 2000, want: 1250},
+		{name: "pembulatan ke bawah", stok: 2, hpp: 1000, jumlah: 1, hargaBeli: 1001, want: 1000},
+		{name: "pembulatan setengah ke atas", stok: 1, hpp: 1000, jumlah: 1, hargaBeli: 1001, want: 1001},
+		{name: "nilai besar tidak overflow", stok: 1_000_000, hpp: 2_000_000_000, jumlah: 1_000_000, hargaBeli: 2_000_000_000, want: 2_000_000_000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, movingAverageCost(tt.stok, tt.hpp, tt.jumlah, tt.hargaBeli))
+		})
+	}
+}
@@ -0,0 +1,259 @@
+package repository
+
+import (
+	"SIE-SRC/domain"
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type mongoRepoReport struct {
+	DB *mongo.Database
+}
+
+func NewMongoRepoReport(client *mongo.Database) domain.ReportRepository {
+	return &mongoRepoReport{
+		DB: client,
+	}
+}
+
+// matchPeriode membatasi penjualan berdasarkan tanggal transaksi
+func matchPeriode(filter domain.ReportFilter) bson.D {
+	tanggal := bson.M{}
+	if !filter.From.IsZero() {
+		tanggal["$gte"] = filter.From
+	}
+	if !filter.To.IsZero() {
+		tanggal["$lt"] = filter.To
+	}
+	if len(tanggal) == 0 {
+		return bson.D{{Key: "$match", Value: bson.M{}}}
+	}
+	return bson.D{{Key: "$match", Value: bson.M{"tanggal": tanggal}}}
+}
+
+// localTimezone mengembalikan offset zona waktu server, misalnya "+07:00"
+func localTimezone() string {
+	return time.Now().Format("-07:00")
+}
+
+// GetMarginReport menghitung penjualan dan HPP dari snapshot di setiap baris penjualan
+func (rp *mongoRepoReport) GetMarginReport(ctx context.Context, filter domain.ReportFilter) ([]domain.MarginReportRow, error) {
+	pipeline := mongo.Pipeline{
+		matchPeriode(filter),
+		{{Key: "$unwind", Value: "$produk"}},
+	}
+
+	var key, nama interface{}
+	switch filter.GroupBy {
+	case domain.ReportGroupProduk:
+		key = "$produk.id_produk"
+		nama = bson.M{"$last": "$produk.nama_produk"}
+	case domain.ReportGroupInduk:
+		pipeline = append(pipeline,
+			bson.D{{Key: "$lookup", Value: bson.M{
+				"from":         _Produk,
+				"localField":   "produk.id_produk",
+				"foreignField": "_id",
+				"as":           "info",
+			}}},
+		)
+		idInduk := bson.M{"$ifNull": bson.A{bson.M{"$arrayElemAt": bson.A{"$info.id_induk", 0}}, ""}}
+		key = bson.M{"$cond": bson.A{
+			bson.M{"$gt": bson.A{idInduk, ""}},
+			idInduk,
+			"$produk.id_produk",
+		}}
+		nama = bson.M{"$last": "$produk.nama_produk"}
+	case domain.ReportGroupKategori:
+		pipeline = append(pipeline,
+			bson.D{{Key: "$lookup", Value: bson.M{
+				"from":         _Produk,
+				"localField":   "produk.id_produk",
+				"foreignField": "_id",
+				"as":           "info",
+			}}},
+		)
+		key = bson.M{"$ifNull": bson.A{bson.M{"$arrayElemAt": bson.A{"$info.kategori", 0}}, ""}}
+		nama = bson.M{"$first": ""}
+	case domain.ReportGroupPeriode:
+		format := "%Y-%m-%d"
+		if filter.Interval == domain.ReportIntervalMonth {
+			format = "%Y-%m"
+		}
+		key = bson.M{"$dateToString": bson.M{
+			"format":   format,
+			"date":     "$tanggal",
+			"timezone": localTimezone(),
+		}}
+		nama = bson.M{"$first": ""}
+	default:
+		return nil, fmt.Errorf("pengelompokan laporan %s tidak dikenal", filter.GroupBy)
+	}
+
+	// Jumlah dilaporkan dalam satuan dasar; baris lama tanpa konversi sudah dalam satuan dasar
+	jumlahDasar := bson.M{"$multiply": bson.A{
+		"$produk.jumlah_produk",
+		bson.M{"$ifNull": bson.A{"$produk.konversi", 1}},
+	}}
+
+	// Penjualan dihitung setelah diskon promo baris dan bagian diskon transaksi;
+	// baris lama tanpa diskon bernilai subtotal
+	nilaiBersih := bson.M{"$subtract": bson.A{
+		"$produk.subtotal",
+		bson.M{"$add": bson.A{
+			bson.M{"$ifNull": bson.A{"$produk.diskon", 0}},
+			bson.M{"$ifNull": bson.A{"$produk.diskon_transaksi", 0}},
+		}},
+	}}
+
+	// Penjualan dilaporkan tanpa PPN (DPP baris); baris lama tanpa DPP memakai nilai bersih
+	penjualan := bson.M{"$ifNull": bson.A{"$produk.dpp", nilaiBersih}}
+
+	pipeline = append(pipeline,
+		bson.D{{Key: "$group", Value: bson.M{
+			"_id":       key,
+			"nama":      nama,
+			"jumlah":    bson.M{"$sum": jumlahDasar},
+			"penjualan": bson.M{"$sum": penjualan},
+			"hpp": bson.M{"$sum": bson.M{"$multiply": bson.A{
+				bson.M{"$ifNull": bson.A{"$produk.harga_pokok", 0}},
+				"$produk.jumlah_produk",
+			}}},
+			"jumlah_tanpa_hpp": bson.M{"$sum": bson.M{"$cond": bson.A{
+				bson.M{"$gt": bson.A{bson.M{"$ifNull": bson.A{"$produk.harga_pokok", 0}}, 0}},
+				0,
+				jumlahDasar,
+			}}},
+		}}},
+		bson.D{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	)
+
+	// Nama baris induk diambil dari produk induk, produk tanpa induk tetap memakai namanya sendiri
+	if filter.GroupBy == domain.ReportGroupInduk {
+		pipeline = append(pipeline,
+			bson.D{{Key: "$lookup", Value: bson.M{
+				"from":         _ProdukInduk,
+				"localField":   "_id",
+				"foreignField": "_id",
+				"as":           "induk",
+			}}},
+			bson.D{{Key: "$set", Value: bson.M{
+				"nama": bson.M{"$ifNull": bson.A{bson.M{"$arrayElemAt": bson.A{"$induk.nama_produk", 0}}, "$nama"}},
+			}}},
+		)
+	}
+
+	cursor, err := rp.DB.Collection(_Penjualan).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("gagal menghitung laporan margin: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	rows := make([]domain.MarginReportRow, 0)
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("gagal membaca laporan margin: %v", err)
+	}
+
+	return rows, nil
+}
+
+// GetTaxReport merekap rincian pajak penjualan per periode dan tarif.
+// Penjualan lama yang belum memiliki rincian pajak tidak ikut dihitung.
+func (rp *mongoRepoReport) GetTaxReport(ctx context.Context, filter domain.ReportFilter) ([]domain.TaxReportRow, error) {
+	format := "%Y-%m-%d"
+	if filter.Interval == domain.ReportIntervalMonth {
+		format = "%Y-%m"
+	}
+
+	pipeline := mongo.Pipeline{
+		matchPeriode(filter),
+		{{Key: "$unwind", Value: "$pajak"}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{
+				"periode": bson.M{"$dateToString": bson.M{
+					"format":   format,
+					"date":     "$tanggal",
+					"timezone": localTimezone(),
+				}},
+				"tarif": "$pajak.tarif",
+			},
+			"jumlah_transaksi": bson.M{"$sum": 1},
+			"dpp":              bson.M{"$sum": "$pajak.dpp"},
+			"ppn":              bson.M{"$sum": "$pajak.ppn"},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":              0,
+			"periode":          "$_id.periode",
+			"tarif":            "$_id.tarif",
+			"jumlah_transaksi": 1,
+			"dpp":              1,
+			"ppn":              1,
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "periode", Value: 1}, {Key: "tarif", Value: 1}}}},
+	}
+
+	cursor, err := rp.DB.Collection(_Penjualan).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("gagal menghitung laporan pajak: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	rows := make([]domain.TaxReportRow, 0)
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("gagal membaca laporan pajak: %v", err)
+	}
+
+	return rows, nil
+}
+
+// GetPaymentReport menjumlahkan pembayaran per metode, dipecah per periode jika interval diisi.
+// JumlahTransaksi menghitung transaksi yang memakai metode tersebut (split payment terhitung di setiap metode).
+func (rp *mongoRepoReport) GetPaymentReport(ctx context.Context, filter domain.ReportFilter) ([]domain.PaymentReportRow, error) {
+	key := bson.M{"metode": "$pembayaran.metode"}
+	if filter.Interval != "" {
+		format := "%Y-%m-%d"
+		if filter.Interval == domain.ReportIntervalMonth {
+			format = "%Y-%m"
+		}
+		key["periode"] = bson.M{"$dateToString": bson.M{
+			"format":   format,
+			"date":     "$tanggal",
+			"timezone": localTimezone(),
+		}}
+	}
+
+	pipeline := mongo.Pipeline{
+		matchPeriode(filter),
+		{{Key: "$unwind", Value: "$pembayaran"}},
+		{{Key: "$group", Value: bson.M{
+			"_id":       key,
+			"transaksi": bson.M{"$addToSet": "$id_penjualan"},
+			"total":     bson.M{"$sum": "$pembayaran.jumlah"},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":              0,
+			"periode":          "$_id.periode",
+			"metode":           "$_id.metode",
+			"jumlah_transaksi": bson.M{"$size": "$transaksi"},
+			"total":            1,
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "periode", Value: 1}, {Key: "metode", Value: 1}}}},
+	}
+
+	cursor, err := rp.DB.Collection(_Penjualan).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("gagal menghitung laporan pembayaran: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	rows := make([]domain.PaymentReportRow, 0)
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("gagal membaca laporan pembayaran: %v", err)
+	}
+
+	return rows, nil
+}
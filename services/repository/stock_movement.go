@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"SIE-SRC/domain"
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoRepoStockMovement struct {
+	DB *mongo.Database
+}
+
+func NewMongoRepoStockMovement(client *mongo.Database) domain.StockMovementRepository {
+	return &mongoRepoStockMovement{
+		DB: client,
+	}
+}
+
+var _StockMovement = "stock_movements"
+
+// Record mencatat satu atau beberapa mutasi stok ke kartu stok
+func (rp *mongoRepoStockMovement) Record(ctx context.Context, movements ...domain.StockMovement) error {
+	DataMovement := rp.DB.Collection(_StockMovement)
+
+	if len(movements) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	docs := make([]interface{}, 0, len(movements))
+	for _, movement := range movements {
+		if movement.Timestamp.IsZero() {
+			movement.Timestamp = now
+		}
+		docs = append(docs, movement)
+	}
+
+	_, err := DataMovement.InsertMany(ctx, docs)
+	if err != nil {
+		return fmt.Errorf("gagal mencatat kartu stok: %w", err)
+	}
+
+	return nil
+}
+
+// GetByProduk menampilkan kartu stok sebuah produk, urut dari yang paling lama.
+// from dan to boleh kosong (zero time) untuk tanpa batas.
+func (rp *mongoRepoStockMovement) GetByProduk(ctx context.Context, idProduk string, from, to time.Time) ([]domain.StockMovement, error) {
+	DataMovement := rp.DB.Collection(_StockMovement)
+
+	filter := bson.M{"id_produk": idProduk}
+	rangeFilter := bson.M{}
+	if !from.IsZero() {
+		rangeFilter["$gte"] = from
+	}
+	if !to.IsZero() {
+		rangeFilter["$lt"] = to
+	}
+	if len(rangeFilter) > 0 {
+		filter["timestamp"] = rangeFilter
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := DataMovement.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil kartu stok: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	movements := make([]domain.StockMovement, 0)
+	if err := cursor.All(ctx, &movements); err != nil {
+		return nil, fmt.Errorf("gagal membaca kartu stok: %v", err)
+	}
+
+	return movements, nil
+}
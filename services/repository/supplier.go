@@ -0,0 +1,281 @@
+package repository
+
+import (
+	"SIE-SRC/domain"
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoRepoSupplier struct {
+	DB      *mongo.Database
+	Counter domain.CounterRepository
+}
+
+func NewMongoRepoSupplier(client *mongo.Database) domain.SupplierRepository {
+	return &mongoRepoSupplier{
+		DB:      client,
+		Counter: NewMongoRepoCounter(client),
+	}
+}
+
+var (
+	_Supplier      = "supplier"
+	_SupplierPrice = "supplier_price"
+)
+
+var supplierIDFormat = domain.CounterFormat{Prefix: "SP", Width: 3}
+
+// Create menambahkan supplier baru
+func (rp *mongoRepoSupplier) Create(ctx context.Context, bd *domain.Supplier) (domain.Supplier, error) {
+	DataSupplier := rp.DB.Collection(_Supplier)
+
+	if err := bd.Validate(); err != nil {
+		return domain.Supplier{}, err
+	}
+
+	if bd.IDSupplier == "" {
+		seq, err := rp.Counter.NextSequence(ctx, _Supplier, 1)
+		if err != nil {
+			return domain.Supplier{}, fmt.Errorf("gagal generate ID supplier: %v", err)
+		}
+		bd.IDSupplier = formatSequenceID(supplierIDFormat, seq)
+	}
+
+	bd.UpdatedAt = time.Now()
+	bd.IsDeleted = nil
+
+	if _, err := DataSupplier.InsertOne(ctx, bd); err != nil {
+		return domain.Supplier{}, fmt.Errorf("gagal menyimpan supplier: %v", err)
+	}
+
+	return *bd, nil
+}
+
+// GetAll menampilkan semua supplier yang belum dihapus
+func (rp *mongoRepoSupplier) GetAll(ctx context.Context) ([]domain.Supplier, error) {
+	DataSupplier := rp.DB.Collection(_Supplier)
+
+	cursor, err := DataSupplier.Find(ctx, bson.M{"is_deleted": nil})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	suppliers := make([]domain.Supplier, 0)
+	if err := cursor.All(ctx, &suppliers); err != nil {
+		return nil, err
+	}
+
+	return suppliers, nil
+}
+
+// GetByID mendapatkan supplier berdasarkan ID
+func (rp *mongoRepoSupplier) GetByID(ctx context.Context, id string) (*domain.Supplier, error) {
+	DataSupplier := rp.DB.Collection(_Supplier)
+
+	var supplier domain.Supplier
+	err := DataSupplier.FindOne(ctx, bson.M{"_id": id, "is_deleted": nil}).Decode(&supplier)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("supplier dengan ID %s tidak ditemukan", id)
+		}
+		return nil, fmt.Errorf("gagal mendapatkan supplier: %v", err)
+	}
+
+	return &supplier, nil
+}
+
+// Update memperbarui data master supplier
+func (rp *mongoRepoSupplier) Update(ctx context.Context, bd *domain.Supplier) error {
+	DataSupplier := rp.DB.Collection(_Supplier)
+
+	if err := bd.Validate(); err != nil {
+		return err
+	}
+
+	bd.UpdatedAt = time.Now()
+
+	result, err := DataSupplier.UpdateOne(ctx, bson.M{"_id": bd.IDSupplier, "is_deleted": nil}, bson.M{
+		"$set": bson.M{
+			"nama_supplier":     bd.NamaSupplier,
+			"nama_kontak":       bd.NamaKontak,
+			"telepon":           bd.Telepon,
+			"email":             bd.Email,
+			"alamat":            bd.Alamat,
+			"npwp":              bd.NPWP,
+			"termin_pembayaran": bd.TerminPembayaran,
+			"catatan":           bd.Catatan,
+			"updated_at":        bd.UpdatedAt,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("gagal memperbarui supplier: %v", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("supplier dengan ID %s tidak ditemukan", bd.IDSupplier)
+	}
+
+	return nil
+}
+
+// Delete menghapus (soft delete) supplier yang tidak memiliki PO aktif dan tidak dipakai produk.
+// Produk yang masih merujuk supplier terhapus akan gagal divalidasi saat diedit atau diimpor.
+func (rp *mongoRepoSupplier) Delete(ctx context.Context, id string) error {
+	DataSupplier := rp.DB.Collection(_Supplier)
+	DataPO := rp.DB.Collection(_PurchaseOrder)
+	DataProduk := rp.DB.Collection(_Produk)
+
+	activePO, err := DataPO.CountDocuments(ctx, bson.M{
+		"id_supplier": id,
+		"status":      bson.M{"$in": bson.A{domain.POStatusOpen, domain.POStatusPartial}},
+	})
+	if err != nil {
+		return fmt.Errorf("gagal memeriksa purchase order supplier: %v", err)
+	}
+	if activePO > 0 {
+		return fmt.Errorf("supplier %s masih memiliki %d purchase order aktif", id, activePO)
+	}
+
+	usedBy, err := DataProduk.CountDocuments(ctx, bson.M{"id_supplier": id, "is_deleted": nil})
+	if err != nil {
+		return fmt.Errorf("gagal memeriksa produk supplier: %v", err)
+	}
+	if usedBy > 0 {
+		return fmt.Errorf("supplier %s masih dipakai oleh %d produk", id, usedBy)
+	}
+
+	now := time.Now()
+	result, err := DataSupplier.UpdateOne(ctx, bson.M{"_id": id, "is_deleted": nil}, bson.M{
+		"$set": bson.M{
+			"is_deleted": now,
+			"updated_at": now,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("gagal menghapus supplier: %v", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("supplier dengan ID %s tidak ditemukan", id)
+	}
+
+	return nil
+}
+
+// GetPriceList menampilkan daftar harga beli dari supplier
+func (rp *mongoRepoSupplier) GetPriceList(ctx context.Context, id string) ([]domain.SupplierPrice, error) {
+	DataPrice := rp.DB.Collection(_SupplierPrice)
+
+	opts := options.Find().SetSort(bson.M{"nama_produk": 1})
+	cursor, err := DataPrice.Find(ctx, bson.M{"id_supplier": id}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	prices := make([]domain.SupplierPrice, 0)
+	if err := cursor.All(ctx, &prices); err != nil {
+		return nil, err
+	}
+
+	return prices, nil
+}
+
+// ImportPriceList menyimpan daftar harga beli supplier.
+// Produk dicocokkan lewat ID atau barcode; harga lama untuk produk yang sama ditimpa.
+func (rp *mongoRepoSupplier) ImportPriceList(ctx context.Context, id string, prices []domain.SupplierPrice) (domain.SupplierPriceImportResult, error) {
+	DataProduk := rp.DB.Collection(_Produk)
+	DataPrice := rp.DB.Collection(_SupplierPrice)
+
+	result := domain.SupplierPriceImportResult{Skipped: []string{}}
+
+	if _, err := rp.GetByID(ctx, id); err != nil {
+		return result, err
+	}
+	if len(prices) == 0 {
+		return result, fmt.Errorf("tidak ada data harga untuk diimpor")
+	}
+
+	keys := make([]string, 0, len(prices))
+	for _, price := range prices {
+		if price.IDProduk != "" {
+			keys = append(keys, price.IDProduk)
+		}
+		if price.KodeProduk != "" {
+			keys = append(keys, price.KodeProduk)
+		}
+	}
+
+	cursor, err := DataProduk.Find(ctx, bson.M{
+		"is_deleted": nil,
+		"$or": bson.A{
+			bson.M{"_id": bson.M{"$in": keys}},
+			bson.M{"barcode_produk": bson.M{"$in": keys}},
+		},
+	})
+	if err != nil {
+		return result, fmt.Errorf("gagal mencari produk: %v", err)
+	}
+	var products []domain.Produk
+	if err := cursor.All(ctx, &products); err != nil {
+		return result, fmt.Errorf("gagal membaca produk: %v", err)
+	}
+
+	byID := make(map[string]domain.Produk, len(products))
+	byBarcode := make(map[string]domain.Produk, len(products))
+	for _, produk := range products {
+		byID[produk.IDProduk] = produk
+		if produk.KodeProduk != "" {
+			byBarcode[produk.KodeProduk] = produk
+		}
+	}
+
+	now := time.Now()
+	var operations []mongo.WriteModel
+	for i, price := range prices {
+		produk, ok := byID[price.IDProduk]
+		if !ok {
+			produk, ok = byBarcode[price.KodeProduk]
+		}
+		if !ok || price.HargaBeli < 0 {
+			log.Printf("Skip harga #%d: produk %q/%q tidak ditemukan atau harga tidak valid",
+				i+1, price.IDProduk, price.KodeProduk)
+			result.Skipped = append(result.Skipped, fmt.Sprintf("baris %d", i+1))
+			continue
+		}
+
+		filter := bson.M{"id_supplier": id, "id_produk": produk.IDProduk}
+		update := bson.M{
+			"$set": bson.M{
+				"barcode_produk": produk.KodeProduk,
+				"nama_produk":    produk.NamaProduk,
+				"harga_beli":     price.HargaBeli,
+				"min_order":      price.MinOrder,
+				"updated_at":     now,
+			},
+		}
+		operations = append(operations, mongo.NewUpdateOneModel().
+			SetFilter(filter).
+			SetUpdate(update).
+			SetUpsert(true))
+	}
+
+	if len(operations) == 0 {
+		return result, fmt.Errorf("semua data harga (%d) dilewati karena tidak valid", len(prices))
+	}
+
+	if _, err := DataPrice.BulkWrite(ctx, operations, options.BulkWrite().SetOrdered(false)); err != nil {
+		return result, fmt.Errorf("gagal menyimpan daftar harga: %v", err)
+	}
+
+	result.Imported = len(operations)
+	log.Printf("Berhasil mengimpor %d harga supplier %s, %d dilewati", result.Imported, id, len(result.Skipped))
+	return result, nil
+}
@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"SIE-SRC/domain"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+type localStorage struct {
+	root    string
+	baseURL string
+}
+
+// NewLocalStorage menyimpan file di folder lokal; folder yang sama disajikan Fiber di baseURL
+func NewLocalStorage(root, baseURL string) domain.FileStorage {
+	return &localStorage{
+		root:    root,
+		baseURL: strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// resolve mengubah key menjadi path di dalam root dan menolak key yang keluar dari root
+func (s *localStorage) resolve(key string) (string, error) {
+	clean := path.Clean("/" + key)
+	if clean == "/" {
+		return "", fmt.Errorf("key file tidak valid: %s", key)
+	}
+	return filepath.Join(s.root, filepath.FromSlash(clean)), nil
+}
+
+func (s *localStorage) Save(ctx context.Context, key string, r io.Reader) error {
+	target, err := s.resolve(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return fmt.Errorf("gagal membuat folder penyimpanan: %v", err)
+	}
+
+	// Tulis ke file sementara lalu rename agar file yang sedang disajikan tidak terbaca setengah
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("gagal membuat file: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return fmt.Errorf("gagal menyimpan file: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("gagal menyimpan file: %v", err)
+	}
+
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return fmt.Errorf("gagal menyimpan file: %v", err)
+	}
+	return nil
+}
+
+func (s *localStorage) Delete(ctx context.Context, key string) error {
+	target, err := s.resolve(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("gagal menghapus file: %v", err)
+	}
+	return nil
+}
+
+func (s *localStorage) URL(key string) string {
+	return s.baseURL + path.Clean("/"+key)
+}
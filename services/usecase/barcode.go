@@ -0,0 +1,254 @@
+package usecase
+
+import (
+	"SIE-SRC/domain"
+	"bytes"
+	"context"
+	"fmt"
+	"image/png"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/code128"
+	"github.com/boombuler/barcode/ean"
+	"github.com/go-pdf/fpdf"
+)
+
+const (
+	defaultBarcodeWidth  = 300
+	defaultBarcodeHeight = 100
+	maxBarcodeSize       = 2000
+	maxLabelCount        = 1000
+)
+
+// Tata letak lembar label A4 3 x 8 (70 x 37 mm per label), ukuran dalam mm
+const (
+	labelColumns = 3
+	labelRows    = 8
+	labelWidth   = 70.0
+	labelHeight  = 37.0
+	labelPadding = 3.0
+	labelMarginY = (297.0 - labelRows*labelHeight) / 2
+)
+
+type BarcodeUseCase struct {
+	ProdukRepository domain.ProdukRepository
+	contextTimeout   time.Duration
+}
+
+func NewUseCaseBarcode(PR domain.ProdukRepository, T time.Duration) domain.BarcodeUseCase {
+	return &BarcodeUseCase{
+		ProdukRepository: PR,
+		contextTimeout:   T,
+	}
+}
+
+// formatRibuan menulis angka dengan pemisah ribuan titik, misal 12.500
+func formatRibuan(nominal int) string {
+	sign := ""
+	if nominal < 0 {
+		sign = "-"
+		nominal = -nominal
+	}
+
+	digits := strconv.Itoa(nominal)
+	var b strings.Builder
+	for i, r := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(r)
+	}
+	return sign + b.String()
+}
+
+// formatRupiah menulis nominal dalam rupiah, misal Rp 12.500
+func formatRupiah(nominal int) string {
+	if nominal < 0 {
+		return "-Rp " + formatRibuan(-nominal)
+	}
+	return "Rp " + formatRibuan(nominal)
+}
+
+// encodeBarcode membuat barcode dari kode produk. Format kosong memilih EAN-13
+// jika kode adalah EAN-13 yang valid, selain itu Code128.
+func encodeBarcode(code, format string) (barcode.Barcode, error) {
+	if code == "" {
+		return nil, fmt.Errorf("produk belum memiliki barcode")
+	}
+
+	if format == "" {
+		format = domain.BarcodeFormatCode128
+		if domain.IsEAN13(code) {
+			format = domain.BarcodeFormatEAN13
+		}
+	}
+
+	switch format {
+	case domain.BarcodeFormatEAN13:
+		if !domain.IsEAN13(code) {
+			return nil, fmt.Errorf("barcode %s bukan EAN-13 yang valid", code)
+		}
+		return ean.Encode(code)
+	case domain.BarcodeFormatCode128:
+		bc, err := code128.Encode(code)
+		if err != nil {
+			return nil, fmt.Errorf("barcode %s tidak bisa dibuat sebagai Code128: %v", code, err)
+		}
+		return bc, nil
+	}
+	return nil, fmt.Errorf("format barcode %s tidak dikenal, gunakan %s atau %s",
+		format, domain.BarcodeFormatCode128, domain.BarcodeFormatEAN13)
+}
+
+// renderBarcodePNG memperbesar barcode ke ukuran yang diminta (minimal 1 pixel per modul)
+func renderBarcodePNG(bc barcode.Barcode, width, height int) ([]byte, error) {
+	if minWidth := bc.Bounds().Dx(); width < minWidth {
+		width = minWidth
+	}
+
+	scaled, err := barcode.Scale(bc, width, height)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membuat gambar barcode: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, scaled); err != nil {
+		return nil, fmt.Errorf("gagal membuat gambar barcode: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// GetImage membuat gambar PNG barcode dari barcode_produk
+func (uc *BarcodeUseCase) GetImage(Ctx context.Context, idProduk string, opts domain.BarcodeImage) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	defer cancel()
+
+	if opts.Width == 0 {
+		opts.Width = defaultBarcodeWidth
+	}
+	if opts.Height == 0 {
+		opts.Height = defaultBarcodeHeight
+	}
+	if opts.Width < 0 || opts.Height < 0 || opts.Width > maxBarcodeSize || opts.Height > maxBarcodeSize {
+		return nil, fmt.Errorf("ukuran gambar barcode harus antara 1 dan %d pixel", maxBarcodeSize)
+	}
+
+	produk, err := uc.ProdukRepository.GetProdukById(ctx, idProduk)
+	if err != nil {
+		return nil, err
+	}
+
+	bc, err := encodeBarcode(produk.KodeProduk, opts.Format)
+	if err != nil {
+		return nil, err
+	}
+	return renderBarcodePNG(bc, opts.Width, opts.Height)
+}
+
+// GenerateLabels membuat PDF lembar label berisi nama, harga dan barcode produk
+func (uc *BarcodeUseCase) GenerateLabels(Ctx context.Context, items []domain.LabelItem) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	defer cancel()
+
+	if len(items) == 0 {
+		return nil, fmt.Errorf("daftar produk untuk label kosong")
+	}
+
+	total := 0
+	for _, item := range items {
+		if item.Jumlah <= 0 {
+			return nil, fmt.Errorf("jumlah label produk %s harus lebih dari 0", item.IDProduk)
+		}
+		total += item.Jumlah
+	}
+	if total > maxLabelCount {
+		return nil, fmt.Errorf("maksimal %d label per cetak", maxLabelCount)
+	}
+
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(0, 0, 0)
+	pdf.SetAutoPageBreak(false, 0)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	position := 0
+	for _, item := range items {
+		produk, err := uc.ProdukRepository.GetProdukById(ctx, item.IDProduk)
+		if err != nil {
+			return nil, err
+		}
+
+		bc, err := encodeBarcode(produk.KodeProduk, "")
+		if err != nil {
+			return nil, fmt.Errorf("produk %s: %v", produk.IDProduk, err)
+		}
+		// Resolusi 4 pixel per modul agar tetap tajam saat dicetak
+		image, err := renderBarcodePNG(bc, bc.Bounds().Dx()*4, 160)
+		if err != nil {
+			return nil, err
+		}
+		imageName := "barcode-" + produk.IDProduk
+		pdf.RegisterImageOptionsReader(imageName, fpdf.ImageOptions{ImageType: "PNG"}, bytes.NewReader(image))
+
+		for i := 0; i < item.Jumlah; i++ {
+			cell := position % (labelColumns * labelRows)
+			if cell == 0 {
+				pdf.AddPage()
+			}
+			x := float64(cell%labelColumns) * labelWidth
+			y := labelMarginY + float64(cell/labelColumns)*labelHeight
+			drawLabel(pdf, tr, produk, imageName, x, y)
+			position++
+		}
+	}
+
+	if err := pdf.Error(); err != nil {
+		return nil, fmt.Errorf("gagal membuat PDF label: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("gagal membuat PDF label: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// drawLabel menulis satu label di posisi x, y
+func drawLabel(pdf *fpdf.Fpdf, tr func(string) string, produk *domain.Produk, imageName string, x, y float64) {
+	innerWidth := labelWidth - 2*labelPadding
+
+	pdf.SetFont("Helvetica", "B", 8)
+	pdf.SetXY(x+labelPadding, y+labelPadding)
+	pdf.CellFormat(innerWidth, 4, fitText(pdf, tr(produk.NamaProduk), innerWidth), "", 0, "L", false, 0, "")
+
+	pdf.SetFont("Helvetica", "B", 11)
+	pdf.SetXY(x+labelPadding, y+labelPadding+4)
+	pdf.CellFormat(innerWidth, 6, formatRupiah(produk.Harga), "", 0, "L", false, 0, "")
+
+	pdf.ImageOptions(imageName, x+labelPadding, y+labelPadding+11, innerWidth, 14, false, fpdf.ImageOptions{ImageType: "PNG"}, 0, "")
+
+	pdf.SetFont("Helvetica", "", 7)
+	pdf.SetXY(x+labelPadding, y+labelPadding+26)
+	pdf.CellFormat(innerWidth, 3, produk.KodeProduk, "", 0, "C", false, 0, "")
+}
+
+// fitText memotong teks dengan "..." agar muat di lebar yang tersedia
+func fitText(pdf *fpdf.Fpdf, text string, width float64) string {
+	if pdf.GetStringWidth(text) <= width {
+		return text
+	}
+	for len(text) > 0 && pdf.GetStringWidth(text+"...") > width {
+		text = text[:len(text)-1]
+	}
+	return text + "..."
+}
+
+// GenerateMissing memberi barcode EAN-13 internal untuk produk yang belum punya barcode
+func (uc *BarcodeUseCase) GenerateMissing(Ctx context.Context) ([]domain.ProdukBarcode, error) {
+	ctx, cancel := context.WithTimeout(detachedContext(Ctx), uc.contextTimeout)
+	defer cancel()
+
+	return uc.ProdukRepository.GenerateBarcodes(ctx)
+}
@@ -0,0 +1,45 @@
+package usecase
+
+import (
+	"SIE-SRC/domain"
+	"context"
+	"fmt"
+	"time"
+)
+
+type StockBatchUseCase struct {
+	StockBatchRepository domain.StockBatchRepository
+	contextTimeout       time.Duration
+}
+
+func NewUseCaseStockBatch(BR domain.StockBatchRepository, T time.Duration) domain.StockBatchUseCase {
+	return &StockBatchUseCase{
+		StockBatchRepository: BR,
+		contextTimeout:       T,
+	}
+}
+
+func (uc *StockBatchUseCase) Create(Ctx context.Context, bd *domain.StockBatch) (domain.StockBatch, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	defer cancel()
+
+	return uc.StockBatchRepository.Create(ctx, bd)
+}
+
+func (uc *StockBatchUseCase) GetByProduk(Ctx context.Context, idProduk string) ([]domain.StockBatch, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	defer cancel()
+
+	return uc.StockBatchRepository.GetByProduk(ctx, idProduk)
+}
+
+func (uc *StockBatchUseCase) GetExpiring(Ctx context.Context, days int) ([]domain.StockBatch, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	defer cancel()
+
+	if days < 0 {
+		return nil, fmt.Errorf("jumlah hari tidak boleh negatif")
+	}
+
+	return uc.StockBatchRepository.GetExpiring(ctx, time.Duration(days)*24*time.Hour)
+}
@@ -0,0 +1,15 @@
+package usecase
+
+import (
+	"SIE-SRC/domain"
+	"context"
+)
+
+// detachedContext membuat context baru yang tidak ikut dibatalkan bersama request,
+// tetapi tetap membawa user yang sedang login untuk keperluan audit
+func detachedContext(Ctx context.Context) context.Context {
+	if Ctx == nil {
+		return context.Background()
+	}
+	return domain.ContextWithUser(context.Background(), domain.UserFromContext(Ctx))
+}
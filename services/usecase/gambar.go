@@ -0,0 +1,213 @@
+package usecase
+
+import (
+	"SIE-SRC/domain"
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/image/draw"
+	"golang.org/x/image/webp"
+)
+
+// Tipe gambar yang diterima beserta ekstensi file yang disimpan
+var allowedGambarTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+type ProdukGambarUseCase struct {
+	ProdukRepository domain.ProdukRepository
+	Storage          domain.FileStorage
+	maxSize          int64
+	maxPixels        int
+	thumbnailSize    int
+	contextTimeout   time.Duration
+}
+
+func NewUseCaseProdukGambar(PR domain.ProdukRepository, S domain.FileStorage, maxSize int64, maxPixels, thumbnailSize int, T time.Duration) domain.ProdukGambarUseCase {
+	return &ProdukGambarUseCase{
+		ProdukRepository: PR,
+		Storage:          S,
+		maxSize:          maxSize,
+		maxPixels:        maxPixels,
+		thumbnailSize:    thumbnailSize,
+		contextTimeout:   T,
+	}
+}
+
+// decodeGambar membaca gambar sesuai content type hasil deteksi isi file
+func decodeGambar(contentType string, data []byte) (image.Image, error) {
+	reader := bytes.NewReader(data)
+	switch contentType {
+	case "image/jpeg":
+		return jpeg.Decode(reader)
+	case "image/png":
+		return png.Decode(reader)
+	case "image/gif":
+		return gif.Decode(reader)
+	case "image/webp":
+		return webp.Decode(reader)
+	}
+	return nil, fmt.Errorf("tipe gambar %s tidak didukung", contentType)
+}
+
+// makeThumbnail memperkecil gambar agar sisi terpanjangnya maksimal size pixel.
+// Gambar dengan transparansi (png, gif) disimpan sebagai PNG, selainnya JPEG.
+func makeThumbnail(src image.Image, contentType string, size int) ([]byte, string, error) {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width > size || height > size {
+		if width >= height {
+			height = max(1, height*size/width)
+			width = size
+		} else {
+			width = max(1, width*size/height)
+			height = size
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Over, nil)
+
+	var buf bytes.Buffer
+	if contentType == "image/png" || contentType == "image/gif" {
+		if err := png.Encode(&buf, dst); err != nil {
+			return nil, "", fmt.Errorf("gagal membuat thumbnail: %v", err)
+		}
+		return buf.Bytes(), ".png", nil
+	}
+
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 80}); err != nil {
+		return nil, "", fmt.Errorf("gagal membuat thumbnail: %v", err)
+	}
+	return buf.Bytes(), ".jpg", nil
+}
+
+// Upload memvalidasi gambar, menyimpan file asli beserta thumbnail, lalu menambahkannya ke produk
+func (uc *ProdukGambarUseCase) Upload(Ctx context.Context, upload domain.ProdukGambarUpload) (domain.ProdukGambar, error) {
+	ctx, cancel := context.WithTimeout(detachedContext(Ctx), uc.contextTimeout)
+	defer cancel()
+
+	if upload.Size > uc.maxSize {
+		return domain.ProdukGambar{}, fmt.Errorf("ukuran gambar maksimal %d KB", uc.maxSize/1024)
+	}
+
+	if _, err := uc.ProdukRepository.GetProdukById(ctx, upload.IDProduk); err != nil {
+		return domain.ProdukGambar{}, err
+	}
+
+	// Baca satu byte lebih dari batas agar file yang melebihi batas tetap terdeteksi
+	data, err := io.ReadAll(io.LimitReader(upload.File, uc.maxSize+1))
+	if err != nil {
+		return domain.ProdukGambar{}, fmt.Errorf("gagal membaca file: %v", err)
+	}
+	if int64(len(data)) > uc.maxSize {
+		return domain.ProdukGambar{}, fmt.Errorf("ukuran gambar maksimal %d KB", uc.maxSize/1024)
+	}
+
+	// Tipe ditentukan dari isi file, bukan dari nama file atau header yang dikirim client
+	contentType := http.DetectContentType(data)
+	ext, ok := allowedGambarTypes[contentType]
+	if !ok {
+		return domain.ProdukGambar{}, fmt.Errorf("tipe file %s tidak didukung, gunakan JPEG, PNG, GIF atau WebP", contentType)
+	}
+
+	// Dimensi diperiksa dari header sebelum decode; PNG/GIF kecil bisa berdimensi sangat besar
+	// dan menghabiskan memori saat didekode penuh
+	header, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return domain.ProdukGambar{}, fmt.Errorf("file gambar rusak atau tidak valid: %v", err)
+	}
+	if header.Width <= 0 || header.Height <= 0 || int64(header.Width)*int64(header.Height) > int64(uc.maxPixels) {
+		return domain.ProdukGambar{}, fmt.Errorf("dimensi gambar %dx%d melebihi batas %d megapixel",
+			header.Width, header.Height, uc.maxPixels/1000000)
+	}
+
+	src, err := decodeGambar(contentType, data)
+	if err != nil {
+		return domain.ProdukGambar{}, fmt.Errorf("file gambar rusak atau tidak valid: %v", err)
+	}
+	thumbnail, thumbExt, err := makeThumbnail(src, contentType, uc.thumbnailSize)
+	if err != nil {
+		return domain.ProdukGambar{}, err
+	}
+
+	gambar := domain.ProdukGambar{
+		ID:          primitive.NewObjectID(),
+		ContentType: contentType,
+	}
+	gambar.Key = fmt.Sprintf("produk/%s/%s%s", upload.IDProduk, gambar.ID.Hex(), ext)
+	gambar.ThumbnailKey = fmt.Sprintf("produk/%s/%s_thumb%s", upload.IDProduk, gambar.ID.Hex(), thumbExt)
+	gambar.URL = uc.Storage.URL(gambar.Key)
+	gambar.ThumbnailURL = uc.Storage.URL(gambar.ThumbnailKey)
+
+	if err := uc.Storage.Save(ctx, gambar.Key, bytes.NewReader(data)); err != nil {
+		return domain.ProdukGambar{}, err
+	}
+	if err := uc.Storage.Save(ctx, gambar.ThumbnailKey, bytes.NewReader(thumbnail)); err != nil {
+		uc.removeFiles(ctx, gambar)
+		return domain.ProdukGambar{}, err
+	}
+
+	if err := uc.ProdukRepository.AddGambar(ctx, upload.IDProduk, gambar); err != nil {
+		uc.removeFiles(ctx, gambar)
+		return domain.ProdukGambar{}, err
+	}
+
+	return gambar, nil
+}
+
+// removeFiles menghapus file gambar dan thumbnail; kegagalan hanya dicatat karena data produk sudah konsisten
+func (uc *ProdukGambarUseCase) removeFiles(ctx context.Context, gambar domain.ProdukGambar) {
+	for _, key := range []string{gambar.Key, gambar.ThumbnailKey} {
+		if err := uc.Storage.Delete(ctx, key); err != nil {
+			log.Printf("gagal menghapus file %s: %v", key, err)
+		}
+	}
+}
+
+func (uc *ProdukGambarUseCase) Delete(Ctx context.Context, idProduk, idGambar string) error {
+	ctx, cancel := context.WithTimeout(detachedContext(Ctx), uc.contextTimeout)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(idGambar)
+	if err != nil {
+		return fmt.Errorf("ID gambar tidak valid")
+	}
+
+	removed, err := uc.ProdukRepository.RemoveGambar(ctx, idProduk, objectID)
+	if err != nil {
+		return err
+	}
+
+	uc.removeFiles(ctx, *removed)
+	return nil
+}
+
+func (uc *ProdukGambarUseCase) Reorder(Ctx context.Context, idProduk string, ids []string) ([]domain.ProdukGambar, error) {
+	ctx, cancel := context.WithTimeout(detachedContext(Ctx), uc.contextTimeout)
+	defer cancel()
+
+	objectIDs := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		objectID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, fmt.Errorf("ID gambar %s tidak valid", id)
+		}
+		objectIDs = append(objectIDs, objectID)
+	}
+
+	return uc.ProdukRepository.ReorderGambar(ctx, idProduk, objectIDs)
+}
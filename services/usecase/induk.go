@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"SIE-SRC/domain"
+	"context"
+	"time"
+)
+
+type ProdukIndukUseCase struct {
+	ProdukIndukRepository domain.ProdukIndukRepository
+	ProdukRepository      domain.ProdukRepository
+	contextTimeout        time.Duration
+}
+
+func NewUseCaseProdukInduk(IR domain.ProdukIndukRepository, PR domain.ProdukRepository, T time.Duration) domain.ProdukIndukUseCase {
+	return &ProdukIndukUseCase{
+		ProdukIndukRepository: IR,
+		ProdukRepository:      PR,
+		contextTimeout:        T,
+	}
+}
+
+// newProdukGroup menghitung ringkasan stok dan rentang harga varian
+func newProdukGroup(induk *domain.ProdukInduk, varian []domain.Produk) domain.ProdukGroup {
+	group := domain.ProdukGroup{
+		Induk:  induk,
+		Varian: varian,
+	}
+	for i, produk := range varian {
+		group.TotalStok += produk.Stok
+		if i == 0 || produk.Harga < group.HargaMin {
+			group.HargaMin = produk.Harga
+		}
+		if produk.Harga > group.HargaMax {
+			group.HargaMax = produk.Harga
+		}
+	}
+	return group
+}
+
+func (uc *ProdukIndukUseCase) Create(Ctx context.Context, bd *domain.ProdukInduk) (domain.ProdukInduk, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	defer cancel()
+
+	return uc.ProdukIndukRepository.Create(ctx, bd)
+}
+
+func (uc *ProdukIndukUseCase) GetAll(Ctx context.Context) ([]domain.ProdukInduk, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	defer cancel()
+
+	return uc.ProdukIndukRepository.GetAll(ctx)
+}
+
+// GetByID menampilkan produk induk beserta semua variannya
+func (uc *ProdukIndukUseCase) GetByID(Ctx context.Context, id string) (*domain.ProdukGroup, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	defer cancel()
+
+	induk, err := uc.ProdukIndukRepository.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	varian, err := uc.ProdukRepository.FindProduk(ctx, domain.ProdukFilter{IDInduk: id})
+	if err != nil {
+		return nil, err
+	}
+
+	group := newProdukGroup(induk, varian)
+	return &group, nil
+}
+
+func (uc *ProdukIndukUseCase) Update(Ctx context.Context, bd *domain.ProdukInduk) error {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	defer cancel()
+
+	return uc.ProdukIndukRepository.Update(ctx, bd)
+}
+
+func (uc *ProdukIndukUseCase) Delete(Ctx context.Context, id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	defer cancel()
+
+	return uc.ProdukIndukRepository.Delete(ctx, id)
+}
+
+// GetGrouped menampilkan daftar produk yang dikelompokkan per induk.
+// Produk tanpa induk tampil sebagai grup dengan satu varian.
+func (uc *ProdukIndukUseCase) GetGrouped(Ctx context.Context, filter domain.ProdukFilter) ([]domain.ProdukGroup, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	defer cancel()
+
+	products, err := uc.ProdukRepository.FindProduk(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := uc.ProdukIndukRepository.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	induk := make(map[string]*domain.ProdukInduk, len(list))
+	for i := range list {
+		induk[list[i].IDInduk] = &list[i]
+	}
+
+	groups := make([]domain.ProdukGroup, 0)
+	varian := make(map[string][]domain.Produk)
+	order := make([]string, 0)
+	for _, produk := range products {
+		if produk.IDInduk == "" || induk[produk.IDInduk] == nil {
+			groups = append(groups, newProdukGroup(nil, []domain.Produk{produk}))
+			continue
+		}
+		if _, ok := varian[produk.IDInduk]; !ok {
+			order = append(order, produk.IDInduk)
+		}
+		varian[produk.IDInduk] = append(varian[produk.IDInduk], produk)
+	}
+
+	for _, id := range order {
+		groups = append(groups, newProdukGroup(induk[id], varian[id]))
+	}
+
+	return groups, nil
+}
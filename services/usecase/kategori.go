@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"SIE-SRC/domain"
+	"context"
+	"strings"
+	"time"
+)
+
+type KategoriUseCase struct {
+	KategoriRepository domain.KategoriRepository
+	contextTimeout     time.Duration
+}
+
+func NewUseCaseKategori(KR domain.KategoriRepository, T time.Duration) domain.KategoriUseCase {
+	return &KategoriUseCase{
+		KategoriRepository: KR,
+		contextTimeout:     T,
+	}
+}
+
+func (uc *KategoriUseCase) Create(Ctx context.Context, bd *domain.Kategori) (domain.Kategori, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	defer cancel()
+
+	return uc.KategoriRepository.Create(ctx, bd)
+}
+
+// GetTree menyusun kategori utama beserta sub kategorinya dan jumlah produk masing-masing
+func (uc *KategoriUseCase) GetTree(Ctx context.Context) ([]domain.KategoriNode, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	defer cancel()
+
+	list, err := uc.KategoriRepository.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := uc.KategoriRepository.CountProduk(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	perKategori := make(map[string]int)
+	perSub := make(map[[2]string]int)
+	for _, count := range counts {
+		perKategori[count.Kategori] += count.Jumlah
+		perSub[[2]string{count.Kategori, count.SubKategori}] += count.Jumlah
+	}
+
+	tree := make([]domain.KategoriNode, 0)
+	index := make(map[string]int)
+	for _, item := range list {
+		if item.IDParent != "" {
+			continue
+		}
+		index[item.IDKategori] = len(tree)
+		tree = append(tree, domain.KategoriNode{
+			Kategori:     item,
+			JumlahProduk: perKategori[strings.ToLower(item.Nama)],
+		})
+	}
+
+	for _, item := range list {
+		i, ok := index[item.IDParent]
+		if item.IDParent == "" || !ok {
+			continue
+		}
+		key := [2]string{strings.ToLower(tree[i].Nama), strings.ToLower(item.Nama)}
+		tree[i].SubKategori = append(tree[i].SubKategori, domain.KategoriNode{
+			Kategori:     item,
+			JumlahProduk: perSub[key],
+		})
+	}
+
+	return tree, nil
+}
+
+func (uc *KategoriUseCase) GetByID(Ctx context.Context, id string) (*domain.Kategori, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	defer cancel()
+
+	return uc.KategoriRepository.GetByID(ctx, id)
+}
+
+// SetTarifPajak mengubah tarif PPN kategori
+func (uc *KategoriUseCase) SetTarifPajak(Ctx context.Context, id string, tarif *float64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	defer cancel()
+
+	return uc.KategoriRepository.SetTarifPajak(ctx, id, tarif)
+}
+
+func (uc *KategoriUseCase) Rename(Ctx context.Context, id string, nama string) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	defer cancel()
+
+	return uc.KategoriRepository.Rename(ctx, id, nama)
+}
+
+func (uc *KategoriUseCase) Delete(Ctx context.Context, id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	defer cancel()
+
+	return uc.KategoriRepository.Delete(ctx, id)
+}
+
+func (uc *KategoriUseCase) Merge(Ctx context.Context, sourceID, targetID string) (domain.KategoriMergeResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	defer cancel()
+
+	return uc.KategoriRepository.Merge(ctx, sourceID, targetID)
+}
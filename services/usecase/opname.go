@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"SIE-SRC/domain"
+	"context"
+	"time"
+)
+
+type OpnameUseCase struct {
+	OpnameRepository domain.OpnameRepository
+	contextTimeout   time.Duration
+}
+
+func NewUseCaseOpname(OR domain.OpnameRepository, T time.Duration) domain.OpnameUseCase {
+	return &OpnameUseCase{
+		OpnameRepository: OR,
+		contextTimeout:   T,
+	}
+}
+
+func (uc *OpnameUseCase) Create(Ctx context.Context, bd *domain.Opname) (domain.Opname, error) {
+	ctx, cancel := context.WithTimeout(detachedContext(Ctx), uc.contextTimeout)
+	defer cancel()
+
+	return uc.OpnameRepository.Create(ctx, bd)
+}
+
+func (uc *OpnameUseCase) GetAll(Ctx context.Context) ([]domain.Opname, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	defer cancel()
+
+	return uc.OpnameRepository.GetAll(ctx)
+}
+
+func (uc *OpnameUseCase) GetByID(Ctx context.Context, id string) (*domain.Opname, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	defer cancel()
+
+	return uc.OpnameRepository.GetByID(ctx, id)
+}
+
+func (uc *OpnameUseCase) SubmitCounts(Ctx context.Context, id string, items []domain.OpnameItem) (*domain.Opname, error) {
+	ctx, cancel := context.WithTimeout(detachedContext(Ctx), uc.contextTimeout)
+	defer cancel()
+
+	return uc.OpnameRepository.SubmitCounts(ctx, id, items)
+}
+
+func (uc *OpnameUseCase) GetVariance(Ctx context.Context, id string) (*domain.OpnameVariance, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	defer cancel()
+
+	return uc.OpnameRepository.GetVariance(ctx, id)
+}
+
+func (uc *OpnameUseCase) Approve(Ctx context.Context, id string) (*domain.OpnameVariance, error) {
+	ctx, cancel := context.WithTimeout(detachedContext(Ctx), uc.contextTimeout)
+	defer cancel()
+
+	return uc.OpnameRepository.Approve(ctx, id)
+}
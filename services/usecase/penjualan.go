@@ -26,7 +26,7 @@ func (uc *PenjualanUseCase) GetAll(Ctx context.Context) ([]domain.Penjualan, err
 }
 
 func (uc *PenjualanUseCase) CreateBulk(Ctx context.Context, bd []domain.Penjualan) ([]domain.Penjualan, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	ctx, cancel := context.WithTimeout(detachedContext(Ctx), uc.contextTimeout)
 	defer cancel()
 
 	return uc.PenjualanRepository.CreateBulk(ctx, bd)
@@ -40,14 +40,14 @@ func (uc *PenjualanUseCase) GetByID(Ctx context.Context, id string) (*domain.Pen
 }
 
 func (uc *PenjualanUseCase) Update(Ctx context.Context, bd *domain.Penjualan) error {
-	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	ctx, cancel := context.WithTimeout(detachedContext(Ctx), uc.contextTimeout)
 	defer cancel()
 
 	return uc.PenjualanRepository.Update(ctx, bd)
 }
 
 func (uc *PenjualanUseCase) Delete(Ctx context.Context, id string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	ctx, cancel := context.WithTimeout(detachedContext(Ctx), uc.contextTimeout)
 	defer cancel()
 
 	return uc.PenjualanRepository.Delete(ctx, id)
@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"SIE-SRC/domain"
+	"context"
+	"time"
+)
+
+type PriceUseCase struct {
+	ProdukRepository        domain.ProdukRepository
+	PriceHistoryRepository  domain.PriceHistoryRepository
+	PriceScheduleRepository domain.PriceScheduleRepository
+	contextTimeout          time.Duration
+}
+
+func NewUseCasePrice(PR domain.ProdukRepository, HR domain.PriceHistoryRepository, SR domain.PriceScheduleRepository, T time.Duration) domain.PriceUseCase {
+	return &PriceUseCase{
+		ProdukRepository:        PR,
+		PriceHistoryRepository:  HR,
+		PriceScheduleRepository: SR,
+		contextTimeout:          T,
+	}
+}
+
+// GetTimeline menampilkan riwayat harga beserta perubahan yang sudah dijadwalkan
+func (uc *PriceUseCase) GetTimeline(Ctx context.Context, idProduk string) (domain.PriceTimeline, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	defer cancel()
+
+	produk, err := uc.ProdukRepository.GetProdukById(ctx, idProduk)
+	if err != nil {
+		return domain.PriceTimeline{}, err
+	}
+
+	history, err := uc.PriceHistoryRepository.GetByProduk(ctx, idProduk)
+	if err != nil {
+		return domain.PriceTimeline{}, err
+	}
+
+	scheduled, err := uc.PriceScheduleRepository.GetPendingByProduk(ctx, idProduk)
+	if err != nil {
+		return domain.PriceTimeline{}, err
+	}
+
+	return domain.PriceTimeline{
+		IDProduk:     idProduk,
+		HargaSaatIni: produk.Harga,
+		Riwayat:      history,
+		Terjadwal:    scheduled,
+	}, nil
+}
+
+func (uc *PriceUseCase) CreateSchedule(Ctx context.Context, bd *domain.PriceSchedule) (domain.PriceSchedule, error) {
+	ctx, cancel := context.WithTimeout(detachedContext(Ctx), uc.contextTimeout)
+	defer cancel()
+
+	return uc.PriceScheduleRepository.Create(ctx, bd)
+}
+
+func (uc *PriceUseCase) GetPendingSchedules(Ctx context.Context) ([]domain.PriceSchedule, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	defer cancel()
+
+	return uc.PriceScheduleRepository.GetAllPending(ctx)
+}
+
+func (uc *PriceUseCase) CancelSchedule(Ctx context.Context, id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	defer cancel()
+
+	return uc.PriceScheduleRepository.Cancel(ctx, id)
+}
+
+// ApplyDueSchedules dipanggil berkala oleh scheduler di cmd/main.go
+func (uc *PriceUseCase) ApplyDueSchedules(Ctx context.Context) (int, error) {
+	ctx, cancel := context.WithTimeout(Ctx, uc.contextTimeout)
+	defer cancel()
+
+	return uc.PriceScheduleRepository.ApplyDue(ctx, time.Now())
+}
@@ -38,10 +38,17 @@ func (uc *ProdukUseCase) GetAllProduk(Ctx context.Context) ([]domain.Produk, err
 	return uc.ProdukRepository.GetAllProduk(ctx)
 }
 
-func (uc *ProdukUseCase) CreateProduk(Ctx context.Context, bd *domain.Produk) (domain.Produk, error) {
+func (uc *ProdukUseCase) FindProduk(Ctx context.Context, filter domain.ProdukFilter) ([]domain.Produk, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
 	defer cancel()
 
+	return uc.ProdukRepository.FindProduk(ctx, filter)
+}
+
+func (uc *ProdukUseCase) CreateProduk(Ctx context.Context, bd *domain.Produk) (domain.Produk, error) {
+	ctx, cancel := context.WithTimeout(detachedContext(Ctx), uc.contextTimeout)
+	defer cancel()
+
 	return uc.ProdukRepository.CreateProduk(ctx, bd)
 }
 
@@ -60,7 +67,7 @@ func (uc *ProdukUseCase) GetProdukByName(Ctx context.Context, nama string) (*dom
 }
 
 func (uc *ProdukUseCase) UpdateProduk(Ctx context.Context, bd *domain.Produk) error {
-	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	ctx, cancel := context.WithTimeout(detachedContext(Ctx), uc.contextTimeout)
 	defer cancel()
 
 	return uc.ProdukRepository.UpdateProduk(ctx, bd)
@@ -73,6 +80,34 @@ func (uc *ProdukUseCase) DeleteProduk(Ctx context.Context, id string) error {
 	return uc.ProdukRepository.DeleteProduk(ctx, id)
 }
 
+func (uc *ProdukUseCase) GetDeletedProduk(Ctx context.Context) ([]domain.Produk, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	defer cancel()
+
+	return uc.ProdukRepository.GetDeletedProduk(ctx)
+}
+
+func (uc *ProdukUseCase) RestoreProduk(Ctx context.Context, ids []string) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	defer cancel()
+
+	return uc.ProdukRepository.RestoreProduk(ctx, ids)
+}
+
+func (uc *ProdukUseCase) PurgeDeletedProduk(Ctx context.Context, before time.Time) (domain.ProdukPurgeResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	defer cancel()
+
+	return uc.ProdukRepository.PurgeDeletedProduk(ctx, before)
+}
+
+func (uc *ProdukUseCase) GetStockMovements(Ctx context.Context, id string, from, to time.Time) ([]domain.StockMovement, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	defer cancel()
+
+	return uc.ProdukRepository.GetStockMovements(ctx, id, from, to)
+}
+
 func (uc *ProdukUseCase) ImportData(ctx context.Context, produkList []domain.Produk) error {
 	return uc.ProdukRepository.ImportData(ctx, produkList)
 }
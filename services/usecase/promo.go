@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"SIE-SRC/domain"
+	"context"
+	"time"
+)
+
+type PromoUseCase struct {
+	PromoRepository domain.PromoRepository
+	contextTimeout  time.Duration
+}
+
+func NewUseCasePromo(PR domain.PromoRepository, T time.Duration) domain.PromoUseCase {
+	return &PromoUseCase{
+		PromoRepository: PR,
+		contextTimeout:  T,
+	}
+}
+
+func (uc *PromoUseCase) Create(Ctx context.Context, bd *domain.Promo) (domain.Promo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	defer cancel()
+
+	return uc.PromoRepository.Create(ctx, bd)
+}
+
+func (uc *PromoUseCase) GetAll(Ctx context.Context) ([]domain.Promo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	defer cancel()
+
+	return uc.PromoRepository.GetAll(ctx)
+}
+
+// GetBerlaku menampilkan promo yang berlaku pada waktu tertentu, termasuk pengecekan happy hour
+func (uc *PromoUseCase) GetBerlaku(Ctx context.Context, at time.Time) ([]domain.Promo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	defer cancel()
+
+	list, err := uc.PromoRepository.GetAktif(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	berlaku := make([]domain.Promo, 0, len(list))
+	for _, promo := range list {
+		if promo.BerlakuPada(at) {
+			berlaku = append(berlaku, promo)
+		}
+	}
+	return berlaku, nil
+}
+
+func (uc *PromoUseCase) GetByID(Ctx context.Context, id string) (*domain.Promo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	defer cancel()
+
+	return uc.PromoRepository.GetByID(ctx, id)
+}
+
+func (uc *PromoUseCase) Update(Ctx context.Context, bd *domain.Promo) error {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	defer cancel()
+
+	return uc.PromoRepository.Update(ctx, bd)
+}
+
+func (uc *PromoUseCase) Delete(Ctx context.Context, id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	defer cancel()
+
+	return uc.PromoRepository.Delete(ctx, id)
+}
@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"SIE-SRC/domain"
+	"context"
+	"time"
+)
+
+type PurchaseUseCase struct {
+	PurchaseRepository domain.PurchaseRepository
+	contextTimeout     time.Duration
+}
+
+func NewUseCasePurchase(PR domain.PurchaseRepository, T time.Duration) domain.PurchaseUseCase {
+	return &PurchaseUseCase{
+		PurchaseRepository: PR,
+		contextTimeout:     T,
+	}
+}
+
+func (uc *PurchaseUseCase) CreatePO(Ctx context.Context, bd *domain.PurchaseOrder) (domain.PurchaseOrder, error) {
+	ctx, cancel := context.WithTimeout(detachedContext(Ctx), uc.contextTimeout)
+	defer cancel()
+
+	return uc.PurchaseRepository.CreatePO(ctx, bd)
+}
+
+func (uc *PurchaseUseCase) GetAllPO(Ctx context.Context) ([]domain.PurchaseOrder, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	defer cancel()
+
+	return uc.PurchaseRepository.GetAllPO(ctx)
+}
+
+func (uc *PurchaseUseCase) GetPOByID(Ctx context.Context, id string) (*domain.PurchaseOrder, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	defer cancel()
+
+	return uc.PurchaseRepository.GetPOByID(ctx, id)
+}
+
+func (uc *PurchaseUseCase) CancelPO(Ctx context.Context, id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	defer cancel()
+
+	return uc.PurchaseRepository.CancelPO(ctx, id)
+}
+
+func (uc *PurchaseUseCase) Receive(Ctx context.Context, idPO string, bd *domain.GoodsReceipt) (domain.GoodsReceipt, error) {
+	ctx, cancel := context.WithTimeout(detachedContext(Ctx), uc.contextTimeout)
+	defer cancel()
+
+	return uc.PurchaseRepository.Receive(ctx, idPO, bd)
+}
+
+func (uc *PurchaseUseCase) GetAllReceipt(Ctx context.Context) ([]domain.GoodsReceipt, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	defer cancel()
+
+	return uc.PurchaseRepository.GetAllReceipt(ctx)
+}
+
+func (uc *PurchaseUseCase) GetReceiptByID(Ctx context.Context, id string) (*domain.GoodsReceipt, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	defer cancel()
+
+	return uc.PurchaseRepository.GetReceiptByID(ctx, id)
+}
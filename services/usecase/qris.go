@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"SIE-SRC/domain"
+	"bytes"
+	"context"
+	"fmt"
+	"image/png"
+	"time"
+
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/qr"
+)
+
+const (
+	defaultQRISSize = 300
+	maxQRISSize     = 1000
+)
+
+type QRISUseCase struct {
+	PenjualanRepository domain.PenjualanRepository
+	merchant            domain.QRISMerchant
+	contextTimeout      time.Duration
+}
+
+func NewUseCaseQRIS(PR domain.PenjualanRepository, merchant domain.QRISMerchant, T time.Duration) domain.QRISUseCase {
+	return &QRISUseCase{
+		PenjualanRepository: PR,
+		merchant:            merchant,
+		contextTimeout:      T,
+	}
+}
+
+// jumlahQRIS memakai porsi pembayaran QRIS untuk split payment, selain itu total penjualan
+func jumlahQRIS(penjualan *domain.Penjualan) int {
+	jumlah := 0
+	for _, bayar := range penjualan.Pembayaran {
+		if bayar.Metode == domain.MetodeQRIS {
+			jumlah += bayar.Jumlah
+		}
+	}
+	if jumlah > 0 {
+		return jumlah
+	}
+	return penjualan.Total
+}
+
+// GetPayload menyusun payload QRIS dinamis untuk penjualan
+func (uc *QRISUseCase) GetPayload(Ctx context.Context, idPenjualan string) (domain.QRISPayload, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	defer cancel()
+
+	penjualan, err := uc.PenjualanRepository.GetByID(ctx, idPenjualan)
+	if err != nil {
+		return domain.QRISPayload{}, err
+	}
+
+	jumlah := jumlahQRIS(penjualan)
+	payload, err := domain.BuildQRISPayload(uc.merchant, jumlah, penjualan.IDPenjualan)
+	if err != nil {
+		return domain.QRISPayload{}, err
+	}
+
+	return domain.QRISPayload{
+		IDPenjualan: penjualan.IDPenjualan,
+		Jumlah:      jumlah,
+		Payload:     payload,
+	}, nil
+}
+
+// GetImage membuat gambar PNG kode QR dari payload QRIS penjualan
+func (uc *QRISUseCase) GetImage(Ctx context.Context, idPenjualan string, size int) ([]byte, error) {
+	if size == 0 {
+		size = defaultQRISSize
+	}
+	if size < 0 || size > maxQRISSize {
+		return nil, fmt.Errorf("ukuran QR harus antara 1 dan %d pixel", maxQRISSize)
+	}
+
+	payload, err := uc.GetPayload(Ctx, idPenjualan)
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := qr.Encode(payload.Payload, qr.M, qr.Auto)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membuat kode QR: %v", err)
+	}
+	if minSize := code.Bounds().Dx(); size < minSize {
+		size = minSize
+	}
+	scaled, err := barcode.Scale(code, size, size)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membuat kode QR: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, scaled); err != nil {
+		return nil, fmt.Errorf("gagal membuat kode QR: %v", err)
+	}
+	return buf.Bytes(), nil
+}
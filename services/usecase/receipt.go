@@ -0,0 +1,403 @@
+package usecase
+
+import (
+	"SIE-SRC/domain"
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// Perintah ESC/POS yang dipakai untuk struk
+var (
+	escposInit        = []byte{0x1B, 0x40}
+	escposAlignLeft   = []byte{0x1B, 0x61, 0x00}
+	escposAlignCenter = []byte{0x1B, 0x61, 0x01}
+	escposBoldOn      = []byte{0x1B, 0x45, 0x01}
+	escposBoldOff     = []byte{0x1B, 0x45, 0x00}
+	escposDoubleOn    = []byte{0x1D, 0x21, 0x11}
+	escposDoubleOff   = []byte{0x1D, 0x21, 0x00}
+	escposFeed        = []byte{0x1B, 0x64, 0x04}
+	escposCut         = []byte{0x1D, 0x56, 0x42, 0x00}
+)
+
+// receiptLine adalah satu baris struk beserta gayanya. Baris double dicetak dua kali
+// lebih lebar sehingga dibungkus pada setengah jumlah kolom.
+type receiptLine struct {
+	text   string
+	center bool
+	bold   bool
+	double bool
+}
+
+type ReceiptUseCase struct {
+	PenjualanRepository domain.PenjualanRepository
+	store               domain.StoreInfo
+	defaultLebar        int
+	contextTimeout      time.Duration
+}
+
+func NewUseCaseReceipt(PR domain.PenjualanRepository, store domain.StoreInfo, lebar int, T time.Duration) domain.ReceiptUseCase {
+	return &ReceiptUseCase{
+		PenjualanRepository: PR,
+		store:               store,
+		defaultLebar:        lebar,
+		contextTimeout:      T,
+	}
+}
+
+// receiptColumns adalah jumlah karakter per baris font standar printer thermal
+func receiptColumns(lebar int) int {
+	if lebar == domain.ReceiptWidth80 {
+		return 48
+	}
+	return 32
+}
+
+// Render membuat struk penjualan dalam format pdf, escpos, atau text untuk kertas 58/80 mm
+func (uc *ReceiptUseCase) Render(Ctx context.Context, idPenjualan, format string, lebar int) (domain.Receipt, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	defer cancel()
+
+	if lebar == 0 {
+		lebar = uc.defaultLebar
+	}
+	if lebar != domain.ReceiptWidth58 && lebar != domain.ReceiptWidth80 {
+		return domain.Receipt{}, fmt.Errorf("lebar struk harus %d atau %d mm", domain.ReceiptWidth58, domain.ReceiptWidth80)
+	}
+	if format == "" {
+		format = domain.ReceiptFormatPDF
+	}
+
+	penjualan, err := uc.PenjualanRepository.GetByID(ctx, idPenjualan)
+	if err != nil {
+		return domain.Receipt{}, err
+	}
+
+	cols := receiptColumns(lebar)
+	lines := buildReceipt(uc.store, penjualan, cols)
+	filename := "struk-" + penjualan.IDPenjualan
+
+	switch format {
+	case domain.ReceiptFormatText:
+		return domain.Receipt{
+			ContentType: "text/plain; charset=utf-8",
+			Filename:    filename + ".txt",
+			Data:        renderReceiptText(lines, cols),
+		}, nil
+	case domain.ReceiptFormatESCPOS:
+		return domain.Receipt{
+			ContentType: "application/octet-stream",
+			Filename:    filename + ".bin",
+			Data:        renderReceiptESCPOS(lines, cols),
+		}, nil
+	case domain.ReceiptFormatPDF:
+		data, err := renderReceiptPDF(lines, lebar, cols)
+		if err != nil {
+			return domain.Receipt{}, err
+		}
+		return domain.Receipt{
+			ContentType: "application/pdf",
+			Filename:    filename + ".pdf",
+			Data:        data,
+		}, nil
+	}
+
+	return domain.Receipt{}, fmt.Errorf("format struk %s tidak dikenal, gunakan pdf, escpos, atau text", format)
+}
+
+// buildReceipt menyusun isi struk: header toko, info transaksi, baris produk, total, pajak, pembayaran, dan footer
+func buildReceipt(store domain.StoreInfo, bd *domain.Penjualan, cols int) []receiptLine {
+	var lines []receiptLine
+	add := func(text string, center, bold bool) {
+		lines = append(lines, receiptLine{text: text, center: center, bold: bold})
+	}
+	addWrapped := func(text string, center bool) {
+		for _, part := range wrapText(text, cols) {
+			add(part, center, false)
+		}
+	}
+	separator := strings.Repeat("-", cols)
+
+	for _, part := range wrapText(store.Nama, cols/2) {
+		lines = append(lines, receiptLine{text: part, center: true, bold: true, double: true})
+	}
+	addWrapped(store.Alamat, true)
+	if store.Telepon != "" {
+		addWrapped("Telp. "+store.Telepon, true)
+	}
+	if store.NPWP != "" {
+		addWrapped("NPWP "+store.NPWP, true)
+	}
+
+	add(separator, false, false)
+	add("No      : "+bd.IDPenjualan, false, false)
+	add("Tanggal : "+bd.Tanggal.In(time.Local).Format("02/01/2006 15:04"), false, false)
+	add("Kasir   : "+bd.NamaPenjual, false, false)
+	if bd.GrupPelanggan != "" {
+		add("Grup    : "+bd.GrupPelanggan, false, false)
+	}
+	add(separator, false, false)
+
+	subtotal := 0
+	for _, item := range bd.Produk {
+		addWrapped(item.NamaProduk, false)
+		qty := strconv.Itoa(item.JumlahProduk)
+		if item.Satuan != "" {
+			qty += " " + item.Satuan
+		}
+		add(twoColumns("  "+qty+" x "+formatRibuan(item.Harga), formatRibuan(item.Subtotal), cols), false, false)
+		if item.Diskon > 0 {
+			label := "  Diskon"
+			if item.Promo != nil {
+				label += " " + item.Promo.Nama
+			}
+			add(twoColumns(label, formatRibuan(-item.Diskon), cols), false, false)
+		}
+		subtotal += item.Subtotal - item.Diskon
+	}
+	// Penjualan lama belum menyimpan subtotal header
+	if bd.Subtotal > 0 {
+		subtotal = bd.Subtotal
+	}
+
+	add(separator, false, false)
+	add(twoColumns("Subtotal", formatRibuan(subtotal), cols), false, false)
+	if bd.Diskon > 0 {
+		label := "Diskon"
+		if bd.Promo != nil {
+			label += " " + bd.Promo.Nama
+		}
+		add(twoColumns(label, formatRibuan(-bd.Diskon), cols), false, false)
+	}
+
+	pajakLines := func(prefix string) {
+		add(twoColumns(prefix+"DPP", formatRibuan(bd.DPP), cols), false, false)
+		for _, rincian := range bd.Pajak {
+			if rincian.Tarif == 0 {
+				continue
+			}
+			label := prefix + "PPN " + strconv.FormatFloat(rincian.Tarif, 'f', -1, 64) + "%"
+			add(twoColumns(label, formatRibuan(rincian.PPN), cols), false, false)
+		}
+	}
+	if bd.ModePajak == domain.PajakModeEksklusif {
+		pajakLines("")
+	}
+	add(twoColumns("TOTAL", formatRibuan(bd.Total), cols), false, true)
+	if bd.ModePajak == domain.PajakModeInklusif && bd.PPN > 0 {
+		add("Harga sudah termasuk PPN", false, false)
+		pajakLines("  ")
+	}
+
+	if len(bd.Pembayaran) > 0 {
+		add(separator, false, false)
+		for _, bayar := range bd.Pembayaran {
+			label := labelPembayaran(bayar)
+			if bayar.Metode == domain.MetodeTunai {
+				add(twoColumns(label, formatRibuan(bayar.Diterima), cols), false, false)
+				continue
+			}
+			add(twoColumns(label, formatRibuan(bayar.Jumlah), cols), false, false)
+			if bayar.Referensi != "" {
+				add("  Ref "+bayar.Referensi, false, false)
+			}
+		}
+		add(twoColumns("Kembali", formatRibuan(bd.Kembalian), cols), false, false)
+	}
+
+	add(separator, false, false)
+	addWrapped(store.Footer, true)
+
+	return lines
+}
+
+// labelPembayaran menulis nama metode pembayaran beserta providernya
+func labelPembayaran(bayar domain.Pembayaran) string {
+	label := map[string]string{
+		domain.MetodeTunai:    "Tunai",
+		domain.MetodeKartu:    "Kartu",
+		domain.MetodeTransfer: "Transfer",
+		domain.MetodeEWallet:  "E-Wallet",
+		domain.MetodeQRIS:     "QRIS",
+	}[bayar.Metode]
+	if label == "" {
+		label = bayar.Metode
+	}
+	if bayar.Provider != "" {
+		label += " " + bayar.Provider
+	}
+	return label
+}
+
+// wrapText membungkus teks per kata agar tidak melebihi cols karakter
+func wrapText(text string, cols int) []string {
+	var lines []string
+	current := ""
+	for _, word := range strings.Fields(text) {
+		for utf8.RuneCountInString(word) > cols {
+			if current != "" {
+				lines = append(lines, current)
+				current = ""
+			}
+			runes := []rune(word)
+			lines = append(lines, string(runes[:cols]))
+			word = string(runes[cols:])
+		}
+		switch {
+		case current == "":
+			current = word
+		case utf8.RuneCountInString(current)+1+utf8.RuneCountInString(word) <= cols:
+			current += " " + word
+		default:
+			lines = append(lines, current)
+			current = word
+		}
+	}
+	if current != "" {
+		lines = append(lines, current)
+	}
+	return lines
+}
+
+// twoColumns menulis label di kiri dan nilai rata kanan; label yang terlalu panjang dipotong
+func twoColumns(left, right string, cols int) string {
+	space := cols - utf8.RuneCountInString(right) - 1
+	if runes := []rune(left); len(runes) > space {
+		left = string(runes[:max(space, 0)])
+	}
+	return left + strings.Repeat(" ", cols-utf8.RuneCountInString(left)-utf8.RuneCountInString(right)) + right
+}
+
+// centerText menambahkan spasi di kiri agar teks berada di tengah
+func centerText(text string, cols int) string {
+	pad := (cols - utf8.RuneCountInString(text)) / 2
+	if pad <= 0 {
+		return text
+	}
+	return strings.Repeat(" ", pad) + text
+}
+
+func renderReceiptText(lines []receiptLine, cols int) []byte {
+	var b bytes.Buffer
+	for _, line := range lines {
+		text := line.text
+		if line.center {
+			text = centerText(text, cols)
+		}
+		b.WriteString(text)
+		b.WriteByte('\n')
+	}
+	return b.Bytes()
+}
+
+// toASCII mengganti karakter non-ASCII karena printer thermal memakai code page bawaan (PC437),
+// juga karakter kontrol agar nama produk tidak bisa menyisipkan perintah ESC/POS
+func toASCII(text string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r > 0x7E {
+			return '?'
+		}
+		return r
+	}, text)
+}
+
+// renderReceiptESCPOS menghasilkan byte mentah ESC/POS: init, isi struk, feed, lalu potong kertas
+func renderReceiptESCPOS(lines []receiptLine, cols int) []byte {
+	var b bytes.Buffer
+	b.Write(escposInit)
+	for _, line := range lines {
+		if line.center {
+			b.Write(escposAlignCenter)
+		} else {
+			b.Write(escposAlignLeft)
+		}
+		if line.bold {
+			b.Write(escposBoldOn)
+		}
+		if line.double {
+			b.Write(escposDoubleOn)
+		}
+
+		b.WriteString(toASCII(line.text))
+		b.WriteByte('\n')
+
+		if line.double {
+			b.Write(escposDoubleOff)
+		}
+		if line.bold {
+			b.Write(escposBoldOff)
+		}
+	}
+	b.Write(escposAlignLeft)
+	b.Write(escposFeed)
+	b.Write(escposCut)
+	return b.Bytes()
+}
+
+// renderReceiptPDF mencetak struk dengan font Courier selebar kertas thermal.
+// Area cetak 48 mm (58 mm) atau 72 mm (80 mm), sama dengan printer thermal.
+func renderReceiptPDF(lines []receiptLine, lebar, cols int) ([]byte, error) {
+	printable := 48.0
+	if lebar == domain.ReceiptWidth80 {
+		printable = 72.0
+	}
+	margin := (float64(lebar) - printable) / 2
+
+	// Lebar karakter Courier adalah 0,6 x ukuran font
+	charWidth := printable / float64(cols)
+	fontSize := charWidth / 0.6 * 72 / 25.4
+	lineHeight := charWidth * 1.6
+
+	height := 2 * margin
+	for _, line := range lines {
+		if line.double {
+			height += lineHeight * 2
+		} else {
+			height += lineHeight
+		}
+	}
+
+	pdf := fpdf.NewCustom(&fpdf.InitType{
+		OrientationStr: "P",
+		UnitStr:        "mm",
+		Size:           fpdf.SizeType{Wd: float64(lebar), Ht: height},
+	})
+	pdf.SetMargins(margin, margin, margin)
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.AddPage()
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	for _, line := range lines {
+		style := ""
+		if line.bold {
+			style = "B"
+		}
+		size, h := fontSize, lineHeight
+		if line.double {
+			size, h = fontSize*2, lineHeight*2
+		}
+		pdf.SetFont("Courier", style, size)
+
+		align := "L"
+		if line.center {
+			align = "C"
+		}
+		pdf.CellFormat(printable, h, tr(line.text), "", 1, align, false, 0, "")
+	}
+
+	if err := pdf.Error(); err != nil {
+		return nil, fmt.Errorf("gagal membuat struk PDF: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("gagal membuat struk PDF: %v", err)
+	}
+	return buf.Bytes(), nil
+}
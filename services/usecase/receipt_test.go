@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"SIE-SRC/domain"
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func receiptPenjualan() *domain.Penjualan {
+	bd := &domain.Penjualan{
+		IDPenjualan: "PJ-000123",
+		Produk: []domain.ProdukJual{
+			{NamaProduk: "Air Mineral", JumlahProduk: 2, Satuan: "dus", Harga: 60000, Subtotal: 120000},
+			{
+				NamaProduk:   "Susu UHT Cokelat Rendah Lemak Kemasan Keluarga Ekstra Besar",
+				JumlahProduk: 3, Harga: 21000, Subtotal: 63000,
+				Diskon: 6300, Promo: &domain.PromoDipakai{Nama: "Diskon Susu Akhir Pekan Spesial Gajian"},
+			},
+		},
+		Subtotal:  176700,
+		Diskon:    6700,
+		Promo:     &domain.PromoDipakai{Nama: "Belanja Hemat"},
+		ModePajak: domain.PajakModeInklusif,
+		DPP:       153153,
+		PPN:       16847,
+		Pajak:     []domain.PajakRincian{{Tarif: 11, DPP: 153153, PPN: 16847}},
+		Total:     170000,
+		Pembayaran: []domain.Pembayaran{
+			{Metode: domain.MetodeKartu, Provider: "BCA", Jumlah: 100000, Referensi: "123456"},
+			{Metode: domain.MetodeTunai, Jumlah: 70000, Diterima: 100000, Kembalian: 30000},
+		},
+		Kembalian: 30000,
+	}
+	bd.NamaPenjual = "Kasir Satu"
+	bd.Tanggal = time.Date(2026, time.October, 16, 14, 30, 0, 0, time.Local)
+	return bd
+}
+
+func TestBuildReceiptColumns(t *testing.T) {
+	store := domain.StoreInfo{
+		Nama:    "Toko Serba Ada Makmur Sejahtera Jaya",
+		Alamat:  "Jl. Merdeka No. 123, Kelurahan Sukamaju, Kecamatan Sukajadi, Bandung",
+		Telepon: "022-1234567",
+		NPWP:    "01.234.567.8-901.000",
+		Footer:  "Terima kasih atas kunjungan Anda. Barang yang sudah dibeli tidak dapat ditukar.",
+	}
+
+	tests := []struct {
+		name  string
+		lebar int
+		cols  int
+	}{
+		{name: "58 mm", lebar: domain.ReceiptWidth58, cols: 32},
+		{name: "80 mm", lebar: domain.ReceiptWidth80, cols: 48},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cols := receiptColumns(tt.lebar)
+			assert.Equal(t, tt.cols, cols)
+
+			lines := buildReceipt(store, receiptPenjualan(), cols)
+			separator := strings.Repeat("-", cols)
+			separators := 0
+			for _, line := range lines {
+				width := utf8.RuneCountInString(line.text)
+				if line.double {
+					assert.LessOrEqual(t, width, cols/2, "baris double %q", line.text)
+				} else {
+					assert.LessOrEqual(t, width, cols, "baris %q", line.text)
+				}
+				if strings.HasPrefix(line.text, "-") {
+					assert.Equal(t, separator, line.text)
+					separators++
+				}
+			}
+			assert.Equal(t, 5, separators)
+
+			// Baris dua kolom memenuhi lebar struk dengan nilai rata kanan
+			text := string(renderReceiptText(lines, cols))
+			assert.Contains(t, text, "\nTOTAL"+strings.Repeat(" ", cols-len("TOTAL")-len("170.000"))+"170.000\n")
+			assert.Contains(t, text, "\nKembali"+strings.Repeat(" ", cols-len("Kembali")-len("30.000"))+"30.000\n")
+			for _, line := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+				assert.LessOrEqual(t, utf8.RuneCountInString(line), cols, "baris %q", line)
+			}
+		})
+	}
+}
+
+func TestRenderReceiptESCPOS(t *testing.T) {
+	store := domain.StoreInfo{Nama: "Toko Makmur", Footer: "Terima kasih"}
+
+	for _, cols := range []int{32, 48} {
+		bd := receiptPenjualan()
+		bd.Produk[0].NamaProduk = "Kopi\x1b@Susu\x1dV\tManis Gula Aren"
+		bd.NamaPenjual = "Kasir\x1b@ Dua"
+
+		data := renderReceiptESCPOS(buildReceipt(store, bd, cols), cols)
+
+		// Init printer di awal; rata kiri, feed 4 baris, dan potong kertas di akhir
+		assert.True(t, bytes.HasPrefix(data, []byte{0x1B, 0x40}))
+		assert.True(t, bytes.HasSuffix(data, []byte{0x1B, 0x61, 0x00, 0x1B, 0x64, 0x04, 0x1D, 0x56, 0x42, 0x00}))
+		assert.Equal(t, 1, bytes.Count(data, []byte{0x1B, 0x40}), "karakter kontrol dari data tidak boleh menjadi perintah ESC/POS")
+		assert.Equal(t, 1, bytes.Count(data, []byte{0x1D, 0x56}))
+		assert.Contains(t, string(data), "Kopi?@Susu?V Manis")
+		assert.Contains(t, string(data), "Kasir?@ Dua")
+	}
+}
+
+func TestToASCII(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "ASCII tetap", text: "Kopi Susu 250ml @5.000", want: "Kopi Susu 250ml @5.000"},
+		{name: "non ASCII diganti", text: "Café Crème", want: "Caf? Cr?me"},
+		{name: "karakter kontrol diganti", text: "A\x1b@B\x00C\nD\x7f", want: "A?@B?C?D?"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, toASCII(tt.text))
+		})
+	}
+}
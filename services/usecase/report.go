@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"SIE-SRC/domain"
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+type ReportUseCase struct {
+	ReportRepository domain.ReportRepository
+	contextTimeout   time.Duration
+}
+
+func NewUseCaseReport(RR domain.ReportRepository, T time.Duration) domain.ReportUseCase {
+	return &ReportUseCase{
+		ReportRepository: RR,
+		contextTimeout:   T,
+	}
+}
+
+// calculateMargin mengisi margin kotor dan persentasenya terhadap penjualan
+func calculateMargin(row *domain.MarginReportRow) {
+	row.Margin = row.Penjualan - row.HPP
+	if row.Penjualan != 0 {
+		row.MarginPersen = math.Round(float64(row.Margin)/float64(row.Penjualan)*10000) / 100
+	}
+}
+
+func (uc *ReportUseCase) GetMarginReport(Ctx context.Context, filter domain.ReportFilter) (domain.MarginReport, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	defer cancel()
+
+	if filter.GroupBy == "" {
+		filter.GroupBy = domain.ReportGroupProduk
+	}
+	if filter.GroupBy == domain.ReportGroupPeriode {
+		if filter.Interval == "" {
+			filter.Interval = domain.ReportIntervalDay
+		}
+		if filter.Interval != domain.ReportIntervalDay && filter.Interval != domain.ReportIntervalMonth {
+			return domain.MarginReport{}, fmt.Errorf("interval harus day atau month")
+		}
+	} else {
+		filter.Interval = ""
+	}
+
+	rows, err := uc.ReportRepository.GetMarginReport(ctx, filter)
+	if err != nil {
+		return domain.MarginReport{}, err
+	}
+
+	report := domain.MarginReport{
+		GroupBy:  filter.GroupBy,
+		Interval: filter.Interval,
+		Rows:     rows,
+		Total:    domain.MarginReportRow{Key: "total"},
+	}
+	for i := range report.Rows {
+		calculateMargin(&report.Rows[i])
+
+		report.Total.Jumlah += report.Rows[i].Jumlah
+		report.Total.Penjualan += report.Rows[i].Penjualan
+		report.Total.HPP += report.Rows[i].HPP
+		report.Total.JumlahTanpaHPP += report.Rows[i].JumlahTanpaHPP
+	}
+	calculateMargin(&report.Total)
+
+	return report, nil
+}
+
+// GetTaxReport merekap DPP dan PPN per periode (default bulanan, sesuai masa pajak)
+func (uc *ReportUseCase) GetTaxReport(Ctx context.Context, filter domain.ReportFilter) (domain.TaxReport, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	defer cancel()
+
+	if filter.Interval == "" {
+		filter.Interval = domain.ReportIntervalMonth
+	}
+	if filter.Interval != domain.ReportIntervalDay && filter.Interval != domain.ReportIntervalMonth {
+		return domain.TaxReport{}, fmt.Errorf("interval harus day atau month")
+	}
+
+	rows, err := uc.ReportRepository.GetTaxReport(ctx, filter)
+	if err != nil {
+		return domain.TaxReport{}, err
+	}
+
+	report := domain.TaxReport{
+		Interval: filter.Interval,
+		Rows:     rows,
+	}
+	for _, row := range rows {
+		report.TotalDPP += row.DPP
+		report.TotalPPN += row.PPN
+	}
+
+	return report, nil
+}
+
+// GetPaymentReport menampilkan rincian penjualan per metode pembayaran; interval opsional
+func (uc *ReportUseCase) GetPaymentReport(Ctx context.Context, filter domain.ReportFilter) (domain.PaymentReport, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	defer cancel()
+
+	if filter.Interval != "" && filter.Interval != domain.ReportIntervalDay && filter.Interval != domain.ReportIntervalMonth {
+		return domain.PaymentReport{}, fmt.Errorf("interval harus day atau month")
+	}
+
+	rows, err := uc.ReportRepository.GetPaymentReport(ctx, filter)
+	if err != nil {
+		return domain.PaymentReport{}, err
+	}
+
+	report := domain.PaymentReport{
+		Interval: filter.Interval,
+		Rows:     rows,
+	}
+	for _, row := range rows {
+		report.Total += row.Total
+	}
+
+	return report, nil
+}
@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"SIE-SRC/domain"
+	"context"
+	"time"
+)
+
+type SupplierUseCase struct {
+	SupplierRepository domain.SupplierRepository
+	contextTimeout     time.Duration
+}
+
+func NewUseCaseSupplier(SR domain.SupplierRepository, T time.Duration) domain.SupplierUseCase {
+	return &SupplierUseCase{
+		SupplierRepository: SR,
+		contextTimeout:     T,
+	}
+}
+
+func (uc *SupplierUseCase) Create(Ctx context.Context, bd *domain.Supplier) (domain.Supplier, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	defer cancel()
+
+	return uc.SupplierRepository.Create(ctx, bd)
+}
+
+func (uc *SupplierUseCase) GetAll(Ctx context.Context) ([]domain.Supplier, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	defer cancel()
+
+	return uc.SupplierRepository.GetAll(ctx)
+}
+
+func (uc *SupplierUseCase) GetByID(Ctx context.Context, id string) (*domain.Supplier, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	defer cancel()
+
+	return uc.SupplierRepository.GetByID(ctx, id)
+}
+
+func (uc *SupplierUseCase) Update(Ctx context.Context, bd *domain.Supplier) error {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	defer cancel()
+
+	return uc.SupplierRepository.Update(ctx, bd)
+}
+
+func (uc *SupplierUseCase) Delete(Ctx context.Context, id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	defer cancel()
+
+	return uc.SupplierRepository.Delete(ctx, id)
+}
+
+func (uc *SupplierUseCase) GetPriceList(Ctx context.Context, id string) ([]domain.SupplierPrice, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	defer cancel()
+
+	return uc.SupplierRepository.GetPriceList(ctx, id)
+}
+
+func (uc *SupplierUseCase) ImportPriceList(Ctx context.Context, id string, prices []domain.SupplierPrice) (domain.SupplierPriceImportResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+	defer cancel()
+
+	return uc.SupplierRepository.ImportPriceList(ctx, id, prices)
+}